@@ -1 +1,83 @@
-package adminapi
\ No newline at end of file
+package main
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/account"
+	"gocom/main/internal/admin"
+	adminservices "gocom/main/internal/admin/services"
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+func main() {
+	// Load configuration
+	config.LoadConfig()
+
+	// Connect to services
+	db.ConnectMySQL()
+
+	// Categories need unique slugs before the slug index can be created
+	if err := adminservices.BackfillCategorySlugs(db.GetDB()); err != nil {
+		log.Fatal("Failed to backfill category slugs:", err)
+	}
+
+	// Auto-migrate database schemas
+	if err := db.GetDB().AutoMigrate(
+		&models.User{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.Seller{},
+		&models.SellerUser{},
+		&models.KYC{},
+		&models.Category{},
+		&models.CategorySchemaVersion{},
+		&models.Product{},
+		&models.ProductModeration{},
+		&models.ProhibitedKeyword{},
+		&models.RestrictedBrand{},
+		&models.BrandAuthorization{},
+		&models.SearchEvent{},
+		&models.SearchQuery{},
+		&models.SKU{},
+		&models.Media{},
+		&models.AuditLog{},
+	); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	storage.Connect()
+	if err := storage.InitializeBuckets(); err != nil {
+		log.Fatalf("Failed to initialize buckets: %v", err)
+	}
+
+	// Setup Gin
+	gin.SetMode(config.AppConfig.GinMode)
+	r := gin.Default()
+
+	// Add middleware
+	r.Use(errors.ErrorHandler())
+
+	// Setup routes
+	account.SetupRoutes(r)
+	admin.SetupRoutes(r)
+
+	// Presigned links of the local storage backend are served by the API
+	if local, ok := storage.GetStore().(*storage.LocalStore); ok {
+		r.Any(storage.LocalRoutePrefix+"*path", gin.WrapH(local))
+	}
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok", "service": "admin-api"})
+	})
+
+	// Start server
+	log.Printf("🛡️ Admin API server starting on port %s", config.AppConfig.ServerPort)
+	log.Fatal(r.Run(":" + config.AppConfig.ServerPort))
+}
@@ -1 +1,92 @@
-package marketplaceapi
\ No newline at end of file
+package main
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/account"
+	adminservices "gocom/main/internal/admin/services"
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/marketplace"
+	marketplaceservices "gocom/main/internal/marketplace/services"
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+)
+
+func main() {
+	// Load configuration
+	config.LoadConfig()
+
+	// Connect to services
+	db.ConnectMySQL()
+
+	// Categories need unique slugs before the slug index can be created
+	if err := adminservices.BackfillCategorySlugs(db.GetDB()); err != nil {
+		log.Fatal("Failed to backfill category slugs:", err)
+	}
+
+	// Auto-migrate database schemas
+	if err := db.GetDB().AutoMigrate(
+		&models.User{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.Seller{},
+		&models.SellerUser{},
+		&models.Category{},
+		&models.CategorySchemaVersion{},
+		&models.Product{},
+		&models.SKU{},
+		&models.Media{},
+		&models.Inventory{},
+		&models.Review{},
+		&models.SearchEvent{},
+		&models.SearchQuery{},
+		&models.ProhibitedKeyword{},
+	); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Product media links are presigned against the store
+	storage.Connect()
+
+	// Open the search index and keep it in step with product changes
+	search.Connect()
+	search.StartIndexer(config.AppConfig.SearchSyncEvery)
+
+	// Drop logged searches past their retention
+	marketplaceservices.NewCatalogService().StartSearchQueryPruner(config.AppConfig.SearchQueryPruneEvery)
+
+	// Setup Gin
+	gin.SetMode(config.AppConfig.GinMode)
+	r := gin.Default()
+
+	// Client IPs feed search logging, so only listed proxies may set them
+	if err := r.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
+	// Add middleware
+	r.Use(errors.ErrorHandler())
+
+	// Setup routes
+	account.SetupRoutes(r)
+	marketplace.SetupRoutes(r)
+
+	// Presigned links of the local storage backend are served by the API
+	if local, ok := storage.GetStore().(*storage.LocalStore); ok {
+		r.Any(storage.LocalRoutePrefix+"*path", gin.WrapH(local))
+	}
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok", "service": "marketplace-api"})
+	})
+
+	// Start server
+	log.Printf("🛒 Marketplace API server starting on port %s", config.AppConfig.ServerPort)
+	log.Fatal(r.Run(":" + config.AppConfig.ServerPort))
+}
@@ -5,12 +5,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"gocom/main/internal/account"
+	adminservices "gocom/main/internal/admin/services"
 	"gocom/main/internal/common/config"
 	"gocom/main/internal/common/db"
 	"gocom/main/internal/common/errors"
 	"gocom/main/internal/integrations/storage"
 	"gocom/main/internal/models"
 	"gocom/main/internal/seller"
+	sellerServices "gocom/main/internal/seller/services"
 )
 
 func main() {
@@ -25,28 +28,60 @@ func main() {
 	db.ConnectRedis()
 	*/
 
+	// Categories need unique slugs before the slug index can be created
+	if err := adminservices.BackfillCategorySlugs(db.GetDB()); err != nil {
+		log.Fatal("Failed to backfill category slugs:", err)
+	}
+
 	// Auto-migrate database schemas
 	if err := db.GetDB().AutoMigrate(
 		&models.User{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
 		&models.Seller{},
+		&models.SellerUser{},
 		&models.KYC{},
 		&models.SKU{},
 		&models.Inventory{},
 		&models.Media{},
+		&models.UploadSession{},
+		&models.ImportJob{},
+		&models.ExportJob{},
 		&models.Category{},
+		&models.CategorySchemaVersion{},
 		&models.Product{},
+		&models.ProductModeration{},
+		&models.ProhibitedKeyword{},
+		&models.RestrictedBrand{},
+		&models.BrandAuthorization{},
+		&models.SearchEvent{},
 		&models.Address{},
+		&models.AuditLog{},
 	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
-	storage.ConnectMinIO()
+	// SKUs created before SKU codes became unique per seller have no seller_id
+	if err := db.GetDB().Exec(
+		"UPDATE skus JOIN products ON products.id = skus.product_id SET skus.seller_id = products.seller_id WHERE skus.seller_id = 0",
+	).Error; err != nil {
+		log.Fatal("Failed to backfill sku seller ids:", err)
+	}
+
+	storage.Connect()
 	if err := storage.InitializeBuckets(); err != nil {
 		log.Fatalf("Failed to initialize buckets: %v", err)
 	} else {
 		log.Printf("Initialized Buckets!")
 	}
 
+	// Clean up abandoned direct uploads
+	sellerServices.NewUploadService().StartUploadSweeper(config.AppConfig.UploadSweepEvery)
+
+	// Process bulk product imports and catalog exports in the background
+	sellerServices.NewImportService().StartImportWorker(config.AppConfig.CatalogJobPollEvery)
+	sellerServices.NewExportService().StartExportWorker(config.AppConfig.CatalogJobPollEvery)
+
 	// Setup Gin
 	gin.SetMode(config.AppConfig.GinMode)
 	r := gin.Default()
@@ -55,8 +90,14 @@ func main() {
 	r.Use(errors.ErrorHandler())
 
 	// Setup routes
+	account.SetupRoutes(r)
 	seller.SetupRoutes(r)
 
+	// Presigned links of the local storage backend are served by the API
+	if local, ok := storage.GetStore().(*storage.LocalStore); ok {
+		r.Any(storage.LocalRoutePrefix+"*path", gin.WrapH(local))
+	}
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok", "service": "seller-api"})
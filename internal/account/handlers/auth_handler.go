@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/account/services"
+)
+
+type AuthHandler struct {
+	AuthService *services.AuthService
+}
+
+func NewAuthHandler() *AuthHandler {
+	return &AuthHandler{
+		AuthService: services.NewAuthService(),
+	}
+}
+
+// Register user
+// POST /v1/auth/register
+func (ah *AuthHandler) Register(c *gin.Context) {
+	var req services.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ah.AuthService.Register(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    services.NewUserResponse(user),
+		"message": "User registered successfully",
+	})
+}
+
+// Login with email and password
+// POST /v1/auth/login
+func (ah *AuthHandler) Login(c *gin.Context) {
+	var req services.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := ah.AuthService.Login(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tokens,
+	})
+}
+
+// Rotate refresh token
+// POST /v1/auth/refresh
+func (ah *AuthHandler) Refresh(c *gin.Context) {
+	var req services.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := ah.AuthService.Refresh(req.RefreshToken)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tokens,
+	})
+}
+
+// Revoke refresh token
+// POST /v1/auth/logout
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	var req services.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ah.AuthService.Logout(req.RefreshToken); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out successfully",
+	})
+}
+
+func respondAuthError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrInvalidCredentials), stderrors.Is(err, services.ErrInvalidRefreshToken),
+		stderrors.Is(err, services.ErrInvalidMFAToken), stderrors.Is(err, services.ErrInvalidTwoFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrTwoFAAlreadyEnabled), stderrors.Is(err, services.ErrTwoFANotEnrolled),
+		stderrors.Is(err, services.ErrTwoFANotEnabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrAccountLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrAccountInactive):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
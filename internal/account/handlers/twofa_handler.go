@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/account/services"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+)
+
+type TwoFAHandler struct {
+	TwoFAService *services.TwoFAService
+}
+
+func NewTwoFAHandler() *TwoFAHandler {
+	return &TwoFAHandler{
+		TwoFAService: services.NewTwoFAService(),
+	}
+}
+
+// Start 2FA enrolment
+// POST /v1/auth/2fa/enroll
+func (th *TwoFAHandler) Enroll(c *gin.Context) {
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	enrolment, err := th.TwoFAService.Enroll(principal.UserID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    enrolment,
+	})
+}
+
+// Confirm 2FA enrolment with the first code
+// POST /v1/auth/2fa/verify
+func (th *TwoFAHandler) Verify(c *gin.Context) {
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	var req services.TwoFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := th.TwoFAService.Verify(principal.UserID, req.Code)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"recovery_codes": codes},
+		"message": "Two-factor authentication enabled",
+	})
+}
+
+// Regenerate recovery codes
+// POST /v1/auth/2fa/recovery-codes
+func (th *TwoFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	var req services.TwoFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := th.TwoFAService.RegenerateRecoveryCodes(principal.UserID, req.Code)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// Complete login with a TOTP or recovery code
+// POST /v1/auth/login/2fa
+func (th *TwoFAHandler) CompleteLogin(c *gin.Context) {
+	var req services.TwoFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := th.TwoFAService.CompleteLogin(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tokens,
+	})
+}
+
+// Force-reset a user's 2FA
+// POST /v1/admin/users/:id/2fa/reset
+func (th *TwoFAHandler) AdminReset(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	if err := th.TwoFAService.AdminReset(uint(userID), principal.UserID); err != nil {
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication reset",
+	})
+}
@@ -0,0 +1,33 @@
+package account
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/account/handlers"
+	"gocom/main/internal/common/auth"
+)
+
+// SetupRoutes registers the user authentication routes shared by every API
+func SetupRoutes(r *gin.Engine) {
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler()
+	twoFAHandler := handlers.NewTwoFAHandler()
+
+	// Public auth routes
+	authRoutes := r.Group("/v1/auth")
+	{
+		authRoutes.POST("/register", authHandler.Register)
+		authRoutes.POST("/login", authHandler.Login)
+		authRoutes.POST("/login/2fa", twoFAHandler.CompleteLogin)
+		authRoutes.POST("/refresh", authHandler.Refresh)
+		authRoutes.POST("/logout", authHandler.Logout)
+	}
+
+	// Two-factor management for the signed-in user
+	twoFARoutes := authRoutes.Group("/2fa", auth.AuthMiddleware())
+	{
+		twoFARoutes.POST("/enroll", twoFAHandler.Enroll)
+		twoFARoutes.POST("/verify", twoFAHandler.Verify)
+		twoFARoutes.POST("/recovery-codes", twoFAHandler.RegenerateRecoveryCodes)
+	}
+}
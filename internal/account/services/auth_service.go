@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+var (
+	ErrEmailTaken          = errors.New("email or phone already registered")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrAccountLocked       = errors.New("account temporarily locked after repeated failed logins")
+	ErrAccountInactive     = errors.New("account is inactive")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+)
+
+// Compared against when the email is unknown so login timing does not
+// reveal which accounts exist
+var dummyPasswordHash, _ = auth.HashPassword("gocom-dummy-password")
+
+type AuthService struct {
+	DB *gorm.DB
+}
+
+func NewAuthService() *AuthService {
+	return &AuthService{
+		DB: db.GetDB(),
+	}
+}
+
+// Register a new user
+func (as *AuthService) Register(req *RegisterRequest) (*models.User, error) {
+	email := normalizeEmail(req.Email)
+
+	var count int64
+	as.DB.Model(&models.User{}).Where("email = ? OR phone = ?", email, req.Phone).Count(&count)
+	if count > 0 {
+		return nil, ErrEmailTaken
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Name:         req.Name,
+		Email:        email,
+		Phone:        req.Phone,
+		PasswordHash: hash,
+		Role:         models.UserRoleCustomer,
+		Status:       models.UserStatusActive,
+	}
+	if err := as.DB.Create(user).Error; err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Authenticate email and password, locking the account after repeated failures
+func (as *AuthService) Authenticate(email, password string) (*models.User, error) {
+	var user models.User
+	if err := as.DB.Where("email = ?", normalizeEmail(email)).First(&user).Error; err != nil {
+		auth.CheckPassword(dummyPasswordHash, password)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		if err := as.RecordFailedLogin(&user); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Status != models.UserStatusActive {
+		return nil, ErrAccountInactive
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := as.DB.Model(&user).Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &user, nil
+}
+
+// Login with email and password. Users with 2FA enabled get an
+// "mfa pending" token to exchange at /v1/auth/login/2fa instead of tokens.
+func (as *AuthService) Login(req *LoginRequest) (*LoginResponse, error) {
+	user, err := as.Authenticate(req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFAEnabled {
+		mfaToken, err := auth.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	tokens, err := as.IssueTokens(user, "")
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{TokenResponse: tokens}, nil
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already
+// rotated is treated as theft and revokes every token in its family.
+func (as *AuthService) Refresh(refreshToken string) (*TokenResponse, error) {
+	var token models.RefreshToken
+	if err := as.DB.Where("token_hash = ?", auth.HashToken(refreshToken)).First(&token).Error; err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if token.RevokedAt != nil {
+		as.revokeFamily(token.FamilyID)
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	var user models.User
+	if err := as.DB.First(&user, token.UserID).Error; err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Status != models.UserStatusActive {
+		as.revokeFamily(token.FamilyID)
+		return nil, ErrAccountInactive
+	}
+
+	// Revoke the presented token only if nobody else rotated it first
+	now := time.Now()
+	result := as.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", token.ID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		as.revokeFamily(token.FamilyID)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	tokens, newToken, err := as.issueTokens(&user, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	as.DB.Model(&token).Update("replaced_by_id", newToken.ID)
+
+	return tokens, nil
+}
+
+// Logout revokes the refresh token family the token belongs to
+func (as *AuthService) Logout(refreshToken string) error {
+	var token models.RefreshToken
+	if err := as.DB.Where("token_hash = ?", auth.HashToken(refreshToken)).First(&token).Error; err != nil {
+		return ErrInvalidRefreshToken
+	}
+
+	return as.revokeFamily(token.FamilyID)
+}
+
+// IssueTokens creates an access token and a refresh token for the user.
+// An empty familyID starts a new refresh token family.
+func (as *AuthService) IssueTokens(user *models.User, familyID string) (*TokenResponse, error) {
+	tokens, _, err := as.issueTokens(user, familyID)
+	return tokens, err
+}
+
+func (as *AuthService) issueTokens(user *models.User, familyID string) (*TokenResponse, *models.RefreshToken, error) {
+	var memberships []models.SellerUser
+	if err := as.DB.
+		Where("user_id = ? AND status = ?", user.ID, models.SellerUserStatusActive).
+		Find(&memberships).Error; err != nil {
+		return nil, nil, err
+	}
+
+	sellers := make([]auth.SellerMembership, 0, len(memberships))
+	for _, m := range memberships {
+		sellers = append(sellers, auth.SellerMembership{SellerID: m.SellerID, Role: m.Role})
+	}
+
+	accessToken, err := auth.GenerateToken(user.ID, []string{user.Role}, sellers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := auth.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if familyID == "" {
+		if familyID, err = auth.GenerateOpaqueToken(16); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	record := &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(config.AppConfig.JWTRefreshTTL),
+	}
+	if err := as.DB.Create(record).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(config.AppConfig.JWTAccessTTL.Seconds()),
+	}, record, nil
+}
+
+// RecordFailedLogin counts a failed credential check and locks the account
+// once the configured limit is reached. Both steps run in SQL so concurrent
+// failures are all counted.
+func (as *AuthService) RecordFailedLogin(user *models.User) error {
+	return as.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", user.ID).
+			Update("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ? AND failed_login_attempts >= ?", user.ID, config.AppConfig.LoginMaxAttempts).
+			Updates(map[string]interface{}{
+				"failed_login_attempts": 0,
+				"locked_until":          time.Now().Add(config.AppConfig.LoginLockout),
+			}).Error
+	})
+}
+
+func (as *AuthService) revokeFamily(familyID string) error {
+	return as.DB.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Request DTOs
+type RegisterRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Phone    string `json:"phone" binding:"required,min=10,max=15"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Response DTOs
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type LoginResponse struct {
+	*TokenResponse
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+type UserResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Role         string `json:"role"`
+	TwoFAEnabled bool   `json:"two_fa_enabled"`
+}
+
+func NewUserResponse(user *models.User) *UserResponse {
+	return &UserResponse{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Phone:        user.Phone,
+		Role:         user.Role,
+		TwoFAEnabled: user.TwoFAEnabled,
+	}
+}
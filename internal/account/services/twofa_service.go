@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFANotEnrolled    = errors.New("two-factor enrolment has not been started")
+	ErrTwoFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFACode    = errors.New("invalid authentication code")
+	ErrInvalidMFAToken     = errors.New("invalid or expired mfa token")
+)
+
+type TwoFAService struct {
+	DB          *gorm.DB
+	AuthService *AuthService
+}
+
+func NewTwoFAService() *TwoFAService {
+	return &TwoFAService{
+		DB:          db.GetDB(),
+		AuthService: NewAuthService(),
+	}
+}
+
+// Enroll generates a new TOTP secret for the user. 2FA stays disabled until
+// the first code is confirmed with Verify.
+func (ts *TwoFAService) Enroll(userID uint) (*EnrollResponse, error) {
+	var user models.User
+	if err := ts.DB.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TwoFAEnabled {
+		return nil, ErrTwoFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := auth.EncryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.DB.Model(&user).Updates(map[string]interface{}{
+		"two_fa_secret":    encrypted,
+		"two_fa_last_step": 0,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &EnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(config.AppConfig.TwoFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// Verify confirms enrolment with the first code, enables 2FA and returns
+// the initial recovery codes
+func (ts *TwoFAService) Verify(userID uint, code string) ([]string, error) {
+	var user models.User
+	if err := ts.DB.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TwoFAEnabled {
+		return nil, ErrTwoFAAlreadyEnabled
+	}
+	if user.TwoFASecret == "" {
+		return nil, ErrTwoFANotEnrolled
+	}
+
+	if err := ts.checkTOTP(&user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := ts.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("two_fa_enabled", true).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = ts.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	return codes, err
+}
+
+// RegenerateRecoveryCodes invalidates the old recovery codes after checking
+// a current TOTP code
+func (ts *TwoFAService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	var user models.User
+	if err := ts.DB.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if !user.TwoFAEnabled {
+		return nil, ErrTwoFANotEnabled
+	}
+
+	if err := ts.checkTOTP(&user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := ts.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = ts.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	return codes, err
+}
+
+// CompleteLogin exchanges an "mfa pending" token plus a TOTP or recovery
+// code for access and refresh tokens
+func (ts *TwoFAService) CompleteLogin(req *TwoFALoginRequest) (*TokenResponse, error) {
+	userID, err := auth.ValidateMFAToken(req.MFAToken)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	var user models.User
+	if err := ts.DB.First(&user, userID).Error; err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, ErrAccountInactive
+	}
+	if !user.TwoFAEnabled {
+		return nil, ErrTwoFANotEnabled
+	}
+
+	switch {
+	case req.Code != "":
+		err = ts.checkTOTP(&user, req.Code)
+	case req.RecoveryCode != "":
+		err = ts.useRecoveryCode(user.ID, req.RecoveryCode)
+	default:
+		err = ErrInvalidTwoFACode
+	}
+	if err != nil {
+		if recordErr := ts.AuthService.RecordFailedLogin(&user); recordErr != nil {
+			return nil, recordErr
+		}
+		return nil, err
+	}
+
+	return ts.AuthService.IssueTokens(&user, "")
+}
+
+// AdminReset disables 2FA for a user who lost their device, removes their
+// recovery codes, signs them out everywhere and records the action
+func (ts *TwoFAService) AdminReset(userID, actorID uint) error {
+	var user models.User
+	if err := ts.DB.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	return ts.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_fa_enabled":   false,
+			"two_fa_secret":    "",
+			"two_fa_last_step": 0,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.AuditLog{
+			Actor:    models.UserActor(actorID),
+			Action:   "user.2fa_reset",
+			Entity:   "user",
+			EntityID: user.ID,
+		}).Error
+	})
+}
+
+// Validate a TOTP code and remember its step so it cannot be replayed
+func (ts *TwoFAService) checkTOTP(user *models.User, code string) error {
+	secret, err := auth.DecryptSecret(user.TwoFASecret)
+	if err != nil {
+		return err
+	}
+
+	step, err := acceptTOTP(secret, code, user.TwoFALastStep, time.Now())
+	if err != nil {
+		return err
+	}
+
+	result := ts.DB.Model(&models.User{}).
+		Where("id = ? AND two_fa_last_step < ?", user.ID, step).
+		Update("two_fa_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidTwoFACode
+	}
+
+	user.TwoFALastStep = step
+	return nil
+}
+
+// acceptTOTP returns the step of a valid code, refusing steps at or before
+// the last accepted one
+func acceptTOTP(secret, code string, lastStep int64, now time.Time) (int64, error) {
+	step, ok := auth.ValidateTOTP(secret, strings.TrimSpace(code), now)
+	if !ok || step <= lastStep {
+		return 0, ErrInvalidTwoFACode
+	}
+	return step, nil
+}
+
+// Consume a recovery code
+func (ts *TwoFAService) useRecoveryCode(userID uint, code string) error {
+	hash := recoveryCodeHash(code)
+
+	result := ts.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidTwoFACode
+	}
+
+	return nil
+}
+
+func (ts *TwoFAService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: recoveryCodeHash(code),
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// newRecoveryCode returns a random code shown as "xxxxx-xxxxx"
+func newRecoveryCode() (string, error) {
+	raw, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(raw[:5] + "-" + raw[5:10]), nil
+}
+
+// recoveryCodeHash is stored instead of the code. Case, surrounding spaces
+// and the dash do not matter when the code is typed back in.
+func recoveryCodeHash(code string) string {
+	return auth.HashToken(normalizeRecoveryCode(code))
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// Request DTOs
+type TwoFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// Response DTOs
+type EnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
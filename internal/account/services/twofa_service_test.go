@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// The SHA-1 secret from RFC 6238 appendix B, whose code at 59s is "287082"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestAcceptTOTP(t *testing.T) {
+	now := time.Unix(59, 0) // step 1
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantErr  error
+	}{
+		{"fresh code", "287082", 0, nil},
+		{"surrounding spaces", " 287082 ", 0, nil},
+		{"replayed step", "287082", 1, ErrInvalidTwoFACode},
+		{"older than last step", "287082", 5, ErrInvalidTwoFACode},
+		{"wrong code", "123456", 0, ErrInvalidTwoFACode},
+	}
+	for _, tt := range tests {
+		step, err := acceptTOTP(rfcSecret, tt.code, tt.lastStep, now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: acceptTOTP error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && step != 1 {
+			t.Errorf("%s: acceptTOTP step = %d, want 1", tt.name, step)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !format.MatchString(code) {
+			t.Errorf("newRecoveryCode = %q, want xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("newRecoveryCode repeated %q", code)
+		}
+		seen[code] = true
+	}
+
+	stored := recoveryCodeHash("abcde-fgh23")
+	for _, typed := range []string{"abcde-fgh23", "ABCDE-FGH23", "abcdefgh23", " abcde-fgh23\n"} {
+		if recoveryCodeHash(typed) != stored {
+			t.Errorf("recoveryCodeHash(%q) does not match the stored code", typed)
+		}
+	}
+	if recoveryCodeHash("abcde-fgh24") == stored {
+		t.Error("a different code matches the stored hash")
+	}
+}
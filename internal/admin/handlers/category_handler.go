@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/admin/services"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/models"
+)
+
+type CategoryHandler struct {
+	CategoryService *services.CategoryService
+}
+
+func NewCategoryHandler() *CategoryHandler {
+	return &CategoryHandler{
+		CategoryService: services.NewCategoryService(),
+	}
+}
+
+// Get the full category tree, including inactive categories
+// GET /v1/admin/categories
+func (ch *CategoryHandler) GetTree(c *gin.Context) {
+	tree, err := ch.CategoryService.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tree,
+	})
+}
+
+// Create category
+// POST /v1/admin/categories
+func (ch *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req services.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.CreateCategory(principal.UserID, &req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Category created successfully",
+	})
+}
+
+// Rename category or change its slug
+// PATCH /v1/admin/categories/:id
+func (ch *CategoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.UpdateCategory(uint(categoryID), principal.UserID, &req)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Category updated successfully",
+	})
+}
+
+// Move category under another parent
+// POST /v1/admin/categories/:id/move
+func (ch *CategoryHandler) MoveCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.MoveCategory(uint(categoryID), principal.UserID, req.ParentID)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Category moved successfully",
+	})
+}
+
+// Deactivate category
+// POST /v1/admin/categories/:id/deactivate
+func (ch *CategoryHandler) DeactivateCategory(c *gin.Context) {
+	ch.setActive(c, false, "Category deactivated")
+}
+
+// Activate category
+// POST /v1/admin/categories/:id/activate
+func (ch *CategoryHandler) ActivateCategory(c *gin.Context) {
+	ch.setActive(c, true, "Category activated")
+}
+
+func (ch *CategoryHandler) setActive(c *gin.Context, active bool, message string) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.SetActive(uint(categoryID), principal.UserID, active)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": message,
+	})
+}
+
+// Reorder the children of a category
+// PUT /v1/admin/categories/order
+func (ch *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var req services.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	categories, err := ch.CategoryService.ReorderCategories(principal.UserID, req.ParentID, req.CategoryIDs)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+	})
+}
+
+// Replace the attribute schema of a category
+// PUT /v1/admin/categories/:id/schema
+func (ch *CategoryHandler) UpdateSchema(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var schema models.CategorySchema
+	if err := c.ShouldBindJSON(&schema); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.UpdateSchema(uint(categoryID), principal.UserID, &schema)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Attribute schema updated",
+	})
+}
+
+// Set the content scoring rules of a category
+// PUT /v1/admin/categories/:id/scoring-rules
+func (ch *CategoryHandler) UpdateScoringRules(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var rules models.ScoringRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.UpdateScoringRules(uint(categoryID), principal.UserID, &rules)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Scoring rules updated",
+	})
+}
+
+// Make a category inherit its parent's scoring rules again
+// DELETE /v1/admin/categories/:id/scoring-rules
+func (ch *CategoryHandler) ResetScoringRules(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	category, err := ch.CategoryService.UpdateScoringRules(uint(categoryID), principal.UserID, nil)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Scoring rules reset to inherited",
+	})
+}
+
+// List attribute schema versions of a category
+// GET /v1/admin/categories/:id/schema/versions
+func (ch *CategoryHandler) ListSchemaVersions(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	versions, err := ch.CategoryService.ListSchemaVersions(uint(categoryID))
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    versions,
+	})
+}
+
+func respondCategoryError(c *gin.Context, err error) {
+	var fieldErrs validation.FieldErrors
+	switch {
+	case stderrors.As(err, &fieldErrs):
+		c.JSON(http.StatusBadRequest, errors.NewValidationError(fieldErrs))
+	case stderrors.Is(err, services.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrCategorySlugTaken), stderrors.Is(err, services.ErrCategoryCycle):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrCategoryParent), stderrors.Is(err, services.ErrCategorySlugInvalid),
+		stderrors.Is(err, services.ErrCategoryOrderInvalid), stderrors.Is(err, services.ErrCategoryName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/admin/services"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+)
+
+type KYCHandler struct {
+	KYCService *services.KYCService
+}
+
+func NewKYCHandler() *KYCHandler {
+	return &KYCHandler{
+		KYCService: services.NewKYCService(),
+	}
+}
+
+// List KYC review queue
+// GET /v1/admin/kyc
+func (kh *KYCHandler) ListQueue(c *gin.Context) {
+	var filters services.KYCFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents, total, err := kh.KYCService.ListQueue(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"documents": documents,
+			"total":     total,
+			"page":      filters.Page,
+			"limit":     filters.Limit,
+		},
+	})
+}
+
+// Get KYC document with presigned link
+// GET /v1/admin/kyc/:id
+func (kh *KYCHandler) GetDocument(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	document, err := kh.KYCService.GetDocument(uint(documentID))
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    document,
+	})
+}
+
+// Approve KYC document
+// POST /v1/admin/kyc/:id/approve
+func (kh *KYCHandler) ApproveDocument(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	document, err := kh.KYCService.ApproveDocument(uint(documentID), principal.UserID, req.Remarks)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    document,
+		"message": "Document approved",
+	})
+}
+
+// Reject KYC document
+// POST /v1/admin/kyc/:id/reject
+func (kh *KYCHandler) RejectDocument(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	document, err := kh.KYCService.RejectDocument(uint(documentID), principal.UserID, req.Remarks)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    document,
+		"message": "Document rejected",
+	})
+}
+
+func respondKYCError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrKYCNotPending):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
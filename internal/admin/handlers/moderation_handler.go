@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/admin/services"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	sellerservices "gocom/main/internal/seller/services"
+)
+
+type ModerationHandler struct {
+	ModerationService *services.ModerationService
+}
+
+func NewModerationHandler() *ModerationHandler {
+	return &ModerationHandler{
+		ModerationService: services.NewModerationService(),
+	}
+}
+
+// List products awaiting review
+// GET /v1/admin/moderation/queue
+func (mh *ModerationHandler) ListQueue(c *gin.Context) {
+	var filters services.ModerationFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, total, err := mh.ModerationService.ListQueue(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"products": products,
+			"total":    total,
+			"page":     filters.Page,
+			"limit":    filters.Limit,
+		},
+	})
+}
+
+// Get product with moderation history
+// GET /v1/admin/products/:id
+func (mh *ModerationHandler) GetProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	detail, err := mh.ModerationService.GetProduct(uint(productID))
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    detail,
+	})
+}
+
+// Approve product
+// POST /v1/admin/products/:id/approve
+func (mh *ModerationHandler) ApproveProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	product, err := mh.ModerationService.ApproveProduct(uint(productID), principal.UserID, req.Remarks)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"message": "Product approved and published",
+	})
+}
+
+// Reject product
+// POST /v1/admin/products/:id/reject
+func (mh *ModerationHandler) RejectProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.RejectProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	product, err := mh.ModerationService.RejectProduct(uint(productID), principal.UserID, &req)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"message": "Product rejected",
+	})
+}
+
+// List prohibited keywords
+// GET /v1/admin/moderation/keywords
+func (mh *ModerationHandler) ListKeywords(c *gin.Context) {
+	keywords, err := mh.ModerationService.ListKeywords()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keywords,
+	})
+}
+
+// Add prohibited keyword
+// POST /v1/admin/moderation/keywords
+func (mh *ModerationHandler) AddKeyword(c *gin.Context) {
+	var req services.KeywordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	keyword, err := mh.ModerationService.AddKeyword(req.Keyword)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    keyword,
+	})
+}
+
+// Remove prohibited keyword
+// DELETE /v1/admin/moderation/keywords/:id
+func (mh *ModerationHandler) DeleteKeyword(c *gin.Context) {
+	keywordID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	if err := mh.ModerationService.DeleteKeyword(uint(keywordID)); err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Keyword removed",
+	})
+}
+
+// List restricted brands
+// GET /v1/admin/moderation/brands
+func (mh *ModerationHandler) ListRestrictedBrands(c *gin.Context) {
+	brands, err := mh.ModerationService.ListRestrictedBrands()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    brands,
+	})
+}
+
+// Restrict a brand
+// POST /v1/admin/moderation/brands
+func (mh *ModerationHandler) AddRestrictedBrand(c *gin.Context) {
+	var req services.RestrictedBrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	brand, err := mh.ModerationService.AddRestrictedBrand(req.Brand)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    brand,
+	})
+}
+
+// Lift a brand restriction
+// DELETE /v1/admin/moderation/brands/:id
+func (mh *ModerationHandler) DeleteRestrictedBrand(c *gin.Context) {
+	brandID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	if err := mh.ModerationService.DeleteRestrictedBrand(uint(brandID)); err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Brand restriction removed",
+	})
+}
+
+// Authorise a seller for a restricted brand
+// POST /v1/admin/moderation/brands/:id/sellers
+func (mh *ModerationHandler) AuthorizeSeller(c *gin.Context) {
+	brandID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.BrandAuthorizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorization, err := mh.ModerationService.AuthorizeSeller(uint(brandID), req.SellerID)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    authorization,
+	})
+}
+
+// Revoke a seller's brand authorisation
+// DELETE /v1/admin/moderation/brands/:id/sellers/:sellerId
+func (mh *ModerationHandler) RevokeSeller(c *gin.Context) {
+	brandID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	sellerID, err := strconv.ParseUint(c.Param("sellerId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	if err := mh.ModerationService.RevokeSeller(uint(brandID), uint(sellerID)); err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Brand authorization revoked",
+	})
+}
+
+func respondModerationError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrProductNotFound), stderrors.Is(err, services.ErrRestrictionNotFound),
+		stderrors.Is(err, sellerservices.ErrSellerNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrProductNotPending), stderrors.Is(err, services.ErrProductNotRejectable),
+		stderrors.Is(err, services.ErrKeywordExists), stderrors.Is(err, services.ErrBrandExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrInvalidRejection), stderrors.Is(err, services.ErrProductSeller),
+		stderrors.Is(err, services.ErrKeywordInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/admin/services"
+)
+
+type SearchHandler struct {
+	SearchService *services.SearchService
+}
+
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{
+		SearchService: services.NewSearchService(),
+	}
+}
+
+// List marketplace searches that found no products
+// GET /v1/admin/search/zero-results
+func (sh *SearchHandler) ListZeroResultQueries(c *gin.Context) {
+	var filters services.ZeroResultFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	queries, total, err := sh.SearchService.ListZeroResultQueries(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"queries": queries,
+			"total":   total,
+			"page":    filters.Page,
+			"limit":   filters.Limit,
+		},
+	})
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/admin/services"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	sellerservices "gocom/main/internal/seller/services"
+)
+
+type SellerHandler struct {
+	SellerService *services.SellerService
+}
+
+func NewSellerHandler() *SellerHandler {
+	return &SellerHandler{
+		SellerService: services.NewSellerService(),
+	}
+}
+
+// List sellers
+// GET /v1/admin/sellers
+func (sh *SellerHandler) ListSellers(c *gin.Context) {
+	var filters services.SellerFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sellers, total, err := sh.SellerService.ListSellers(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"sellers": sellers,
+			"total":   total,
+			"page":    filters.Page,
+			"limit":   filters.Limit,
+		},
+	})
+}
+
+// Get seller
+// GET /v1/admin/sellers/:id
+func (sh *SellerHandler) GetSeller(c *gin.Context) {
+	sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	seller, err := sh.SellerService.GetSeller(uint(sellerID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+	})
+}
+
+// Approve seller
+// POST /v1/admin/sellers/:id/approve
+func (sh *SellerHandler) ApproveSeller(c *gin.Context) {
+	sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	seller, err := sh.SellerService.ApproveSeller(uint(sellerID), principal.UserID, req.Remarks)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Seller approved",
+	})
+}
+
+// Reject seller
+// POST /v1/admin/sellers/:id/reject
+func (sh *SellerHandler) RejectSeller(c *gin.Context) {
+	sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(c)
+	seller, err := sh.SellerService.RejectSeller(uint(sellerID), principal.UserID, req.Remarks)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Seller rejected",
+	})
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, sellerservices.ErrSellerNotFound), stderrors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrSellerNotPending), stderrors.Is(err, services.ErrSellerIncomplete):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1 +1,82 @@
-package admin
\ No newline at end of file
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	accounthandlers "gocom/main/internal/account/handlers"
+	"gocom/main/internal/admin/handlers"
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/models"
+)
+
+func SetupRoutes(r *gin.Engine) {
+	// Initialize handlers
+	sellerHandler := handlers.NewSellerHandler()
+	kycHandler := handlers.NewKYCHandler()
+	categoryHandler := handlers.NewCategoryHandler()
+	moderationHandler := handlers.NewModerationHandler()
+	searchHandler := handlers.NewSearchHandler()
+	twoFAHandler := accounthandlers.NewTwoFAHandler()
+
+	// Admin API group, every route requires the admin role
+	adminRoutes := r.Group("/v1/admin", auth.AuthMiddleware(), auth.RequireRole(models.UserRoleAdmin))
+
+	// Seller routes
+	{
+		adminRoutes.GET("/sellers", sellerHandler.ListSellers)
+		adminRoutes.GET("/sellers/:id", sellerHandler.GetSeller)
+		adminRoutes.POST("/sellers/:id/approve", sellerHandler.ApproveSeller)
+		adminRoutes.POST("/sellers/:id/reject", sellerHandler.RejectSeller)
+	}
+
+	// KYC review routes
+	{
+		adminRoutes.GET("/kyc", kycHandler.ListQueue)
+		adminRoutes.GET("/kyc/:id", kycHandler.GetDocument)
+		adminRoutes.POST("/kyc/:id/approve", kycHandler.ApproveDocument)
+		adminRoutes.POST("/kyc/:id/reject", kycHandler.RejectDocument)
+	}
+
+	// Category tree routes
+	{
+		adminRoutes.GET("/categories", categoryHandler.GetTree)
+		adminRoutes.POST("/categories", categoryHandler.CreateCategory)
+		adminRoutes.PUT("/categories/order", categoryHandler.ReorderCategories)
+		adminRoutes.PATCH("/categories/:id", categoryHandler.UpdateCategory)
+		adminRoutes.POST("/categories/:id/move", categoryHandler.MoveCategory)
+		adminRoutes.POST("/categories/:id/deactivate", categoryHandler.DeactivateCategory)
+		adminRoutes.POST("/categories/:id/activate", categoryHandler.ActivateCategory)
+		adminRoutes.PUT("/categories/:id/schema", categoryHandler.UpdateSchema)
+		adminRoutes.GET("/categories/:id/schema/versions", categoryHandler.ListSchemaVersions)
+		adminRoutes.PUT("/categories/:id/scoring-rules", categoryHandler.UpdateScoringRules)
+		adminRoutes.DELETE("/categories/:id/scoring-rules", categoryHandler.ResetScoringRules)
+	}
+
+	// Product moderation routes
+	{
+		adminRoutes.GET("/moderation/queue", moderationHandler.ListQueue)
+		adminRoutes.GET("/products/:id", moderationHandler.GetProduct)
+		adminRoutes.POST("/products/:id/approve", moderationHandler.ApproveProduct)
+		adminRoutes.POST("/products/:id/reject", moderationHandler.RejectProduct)
+
+		// Automatic check rules
+		adminRoutes.GET("/moderation/keywords", moderationHandler.ListKeywords)
+		adminRoutes.POST("/moderation/keywords", moderationHandler.AddKeyword)
+		adminRoutes.DELETE("/moderation/keywords/:id", moderationHandler.DeleteKeyword)
+		adminRoutes.GET("/moderation/brands", moderationHandler.ListRestrictedBrands)
+		adminRoutes.POST("/moderation/brands", moderationHandler.AddRestrictedBrand)
+		adminRoutes.DELETE("/moderation/brands/:id", moderationHandler.DeleteRestrictedBrand)
+		adminRoutes.POST("/moderation/brands/:id/sellers", moderationHandler.AuthorizeSeller)
+		adminRoutes.DELETE("/moderation/brands/:id/sellers/:sellerId", moderationHandler.RevokeSeller)
+	}
+
+	// Marketplace search reports
+	{
+		adminRoutes.GET("/search/zero-results", searchHandler.ListZeroResultQueries)
+	}
+
+	// User support routes
+	{
+		adminRoutes.POST("/users/:id/2fa/reset", twoFAHandler.AdminReset)
+	}
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/models"
+)
+
+var (
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrCategoryName         = errors.New("category name must not be blank")
+	ErrCategoryParent       = errors.New("parent category not found")
+	ErrCategoryCycle        = errors.New("a category cannot be moved under itself or its descendants")
+	ErrCategorySlugTaken    = errors.New("seo slug is already used by another category")
+	ErrCategorySlugInvalid  = errors.New("seo slug may only contain lowercase letters, digits and hyphens")
+	ErrCategoryOrderInvalid = errors.New("category_ids must list every child of the parent exactly once")
+)
+
+var (
+	slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+type CategoryService struct {
+	DB *gorm.DB
+}
+
+func NewCategoryService() *CategoryService {
+	return &CategoryService{
+		DB: db.GetDB(),
+	}
+}
+
+// Get the full category tree, including inactive branches
+func (cs *CategoryService) GetTree() ([]models.Category, error) {
+	var categories []models.Category
+	if err := cs.DB.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return models.BuildCategoryTree(categories, true), nil
+}
+
+// Get a single category
+func (cs *CategoryService) GetCategory(categoryID uint) (*models.Category, error) {
+	var category models.Category
+	if err := cs.DB.First(&category, categoryID).Error; err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	return &category, nil
+}
+
+// Create a category at the end of its siblings
+func (cs *CategoryService) CreateCategory(actorID uint, req *CreateCategoryRequest) (*models.Category, error) {
+	category := &models.Category{
+		ParentID: req.ParentID,
+		Name:     strings.TrimSpace(req.Name),
+		IsActive: true,
+	}
+	if category.Name == "" {
+		return nil, ErrCategoryName
+	}
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if req.ParentID != nil {
+			if err := tx.First(&models.Category{}, *req.ParentID).Error; err != nil {
+				return ErrCategoryParent
+			}
+		}
+
+		slug, err := cs.resolveSlug(tx, 0, category.Name, req.SEOSlug)
+		if err != nil {
+			return err
+		}
+		category.SEOSlug = slug
+		category.Sort = cs.nextSort(tx, req.ParentID)
+
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+
+		if req.AttributesSchema != nil {
+			if err := cs.saveSchema(tx, category, req.AttributesSchema, actorID); err != nil {
+				return err
+			}
+		}
+
+		return cs.audit(tx, actorID, "category.create", category.ID, map[string]interface{}{
+			"name":      category.Name,
+			"parent_id": category.ParentID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Rename a category and/or change its slug. The slug is kept on rename so
+// existing links keep working, unless a new one is given explicitly.
+func (cs *CategoryService) UpdateCategory(categoryID, actorID uint, req *UpdateCategoryRequest) (*models.Category, error) {
+	var category models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&category, categoryID).Error; err != nil {
+			return ErrCategoryNotFound
+		}
+
+		// A slug derived from the name follows the new name
+		name := category.Name
+		updates := map[string]interface{}{}
+		if req.Name != nil {
+			name = strings.TrimSpace(*req.Name)
+			if name == "" {
+				return ErrCategoryName
+			}
+			updates["name"] = name
+		}
+		if req.SEOSlug != nil {
+			slug, err := cs.resolveSlug(tx, category.ID, name, req.SEOSlug)
+			if err != nil {
+				return err
+			}
+			updates["seo_slug"] = slug
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&category).Updates(updates).Error; err != nil {
+			return err
+		}
+		return cs.audit(tx, actorID, "category.update", category.ID, updates)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Move a category under a new parent, or to the root when parentID is nil
+func (cs *CategoryService) MoveCategory(categoryID, actorID uint, parentID *uint) (*models.Category, error) {
+	var category models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&category, categoryID).Error; err != nil {
+			return ErrCategoryNotFound
+		}
+
+		if parentID != nil {
+			if err := cs.checkCycle(tx, categoryID, *parentID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&category).Updates(map[string]interface{}{
+			"parent_id": parentID,
+			"sort":      cs.nextSort(tx, parentID),
+		}).Error; err != nil {
+			return err
+		}
+		category.ParentID = parentID
+
+		return cs.audit(tx, actorID, "category.move", category.ID, map[string]interface{}{"parent_id": parentID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Activate or deactivate a category. Deactivating hides the whole branch
+// from the marketplace; the descendants keep their own flag.
+func (cs *CategoryService) SetActive(categoryID, actorID uint, active bool) (*models.Category, error) {
+	var category models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&category, categoryID).Error; err != nil {
+			return ErrCategoryNotFound
+		}
+		if err := tx.Model(&category).Update("is_active", active).Error; err != nil {
+			return err
+		}
+
+		action := "category.deactivate"
+		if active {
+			action = "category.activate"
+		}
+		return cs.audit(tx, actorID, action, category.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Reorder the children of a parent (root when parentID is nil).
+// categoryIDs must contain every child; position becomes Category.Sort.
+func (cs *CategoryService) ReorderCategories(actorID uint, parentID *uint, categoryIDs []uint) ([]models.Category, error) {
+	var children []models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Category{})
+		if parentID != nil {
+			query = query.Where("parent_id = ?", *parentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+		if err := query.Find(&children).Error; err != nil {
+			return err
+		}
+
+		if len(categoryIDs) != len(children) {
+			return ErrCategoryOrderInvalid
+		}
+		position := make(map[uint]int, len(categoryIDs))
+		for i, id := range categoryIDs {
+			if _, dup := position[id]; dup {
+				return ErrCategoryOrderInvalid
+			}
+			position[id] = i
+		}
+
+		for i := range children {
+			sort, ok := position[children[i].ID]
+			if !ok {
+				return ErrCategoryOrderInvalid
+			}
+			if err := tx.Model(&children[i]).Update("sort", sort).Error; err != nil {
+				return err
+			}
+		}
+
+		var auditID uint
+		if parentID != nil {
+			auditID = *parentID
+		}
+		return cs.audit(tx, actorID, "category.reorder", auditID, map[string]interface{}{"category_ids": categoryIDs})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildCategoryTree(children, true), nil
+}
+
+// Replace the attribute schema of a category as a new version
+func (cs *CategoryService) UpdateSchema(categoryID, actorID uint, schema *models.CategorySchema) (*models.Category, error) {
+	var category models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&category, categoryID).Error; err != nil {
+			return ErrCategoryNotFound
+		}
+		if err := cs.saveSchema(tx, &category, schema, actorID); err != nil {
+			return err
+		}
+		return cs.audit(tx, actorID, "category.schema_update", category.ID, map[string]interface{}{"version": category.SchemaVersion})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Set the content scoring rules of a category and its subtree. nil rules
+// make the category inherit its parent's rules again. Stored product
+// scores are refreshed the next time they are edited or scored.
+func (cs *CategoryService) UpdateScoringRules(categoryID, actorID uint, rules *models.ScoringRules) (*models.Category, error) {
+	var category models.Category
+
+	err := cs.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&category, categoryID).Error; err != nil {
+			return ErrCategoryNotFound
+		}
+
+		var data []byte
+		if rules != nil {
+			if fieldErrs := validation.ValidateScoringRules(rules); len(fieldErrs) > 0 {
+				return fieldErrs
+			}
+			var err error
+			if data, err = json.Marshal(rules); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&category).Update("scoring_rules", data).Error; err != nil {
+			return err
+		}
+		category.ScoringRules = data
+
+		action := "category.scoring_update"
+		if rules == nil {
+			action = "category.scoring_reset"
+		}
+		return cs.audit(tx, actorID, action, category.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// List all schema versions of a category, newest first
+func (cs *CategoryService) ListSchemaVersions(categoryID uint) ([]models.CategorySchemaVersion, error) {
+	if _, err := cs.GetCategory(categoryID); err != nil {
+		return nil, err
+	}
+
+	var versions []models.CategorySchemaVersion
+	err := cs.DB.Where("category_id = ?", categoryID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// Validate and store a schema, bumping the category's schema version
+func (cs *CategoryService) saveSchema(tx *gorm.DB, category *models.Category, schema *models.CategorySchema, actorID uint) error {
+	if fieldErrs := validation.ValidateSchema(schema); len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	version := category.SchemaVersion + 1
+	if err := tx.Model(category).Updates(map[string]interface{}{
+		"attributes_schema": data,
+		"schema_version":    version,
+	}).Error; err != nil {
+		return err
+	}
+	category.AttributesSchema = data
+	category.SchemaVersion = version
+
+	return tx.Create(&models.CategorySchemaVersion{
+		CategoryID: category.ID,
+		Version:    version,
+		Schema:     data,
+		CreatedBy:  actorID,
+	}).Error
+}
+
+// Walk up from the new parent; reaching the category itself means the
+// move would create a cycle
+func (cs *CategoryService) checkCycle(tx *gorm.DB, categoryID, parentID uint) error {
+	current := &parentID
+	for current != nil {
+		if *current == categoryID {
+			return ErrCategoryCycle
+		}
+
+		var ancestor models.Category
+		if err := tx.Select("id", "parent_id").First(&ancestor, *current).Error; err != nil {
+			return ErrCategoryParent
+		}
+		current = ancestor.ParentID
+	}
+	return nil
+}
+
+// BackfillCategorySlugs gives every category without a slug, or sharing
+// one with an older category, a unique slug derived from its name. It runs
+// before AutoMigrate, which cannot add the unique slug index otherwise.
+func BackfillCategorySlugs(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasTable(&models.Category{}) || !migrator.HasColumn(&models.Category{}, "SEOSlug") {
+		return nil
+	}
+
+	var categories []models.Category
+	if err := tx.Select("id", "name", "seo_slug").Order("id ASC").Find(&categories).Error; err != nil {
+		return err
+	}
+
+	cs := &CategoryService{DB: tx}
+	seen := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if category.SEOSlug != "" && !seen[category.SEOSlug] {
+			seen[category.SEOSlug] = true
+			continue
+		}
+
+		slug, err := cs.resolveSlug(tx, category.ID, category.Name, nil)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("id = ?", category.ID).Update("seo_slug", slug).Error; err != nil {
+			return err
+		}
+		seen[slug] = true
+	}
+	return nil
+}
+
+// Use the requested slug if it is free, otherwise derive a unique one from
+// the name by appending -2, -3, ...
+func (cs *CategoryService) resolveSlug(tx *gorm.DB, categoryID uint, name string, requested *string) (string, error) {
+	if requested != nil && *requested != "" {
+		slug := strings.ToLower(strings.TrimSpace(*requested))
+		if !slugPattern.MatchString(slug) {
+			return "", ErrCategorySlugInvalid
+		}
+		if cs.slugTaken(tx, categoryID, slug) {
+			return "", ErrCategorySlugTaken
+		}
+		return slug, nil
+	}
+
+	base := strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "category"
+	}
+
+	slug := base
+	for i := 2; cs.slugTaken(tx, categoryID, slug); i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	return slug, nil
+}
+
+func (cs *CategoryService) slugTaken(tx *gorm.DB, categoryID uint, slug string) bool {
+	var count int64
+	tx.Model(&models.Category{}).Where("seo_slug = ? AND id <> ?", slug, categoryID).Count(&count)
+	return count > 0
+}
+
+// Sort value that places a category after its current siblings
+func (cs *CategoryService) nextSort(tx *gorm.DB, parentID *uint) int {
+	var maxSort *int
+	query := tx.Model(&models.Category{})
+	if parentID != nil {
+		query = query.Where("parent_id = ?", *parentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+	query.Select("MAX(sort)").Scan(&maxSort)
+
+	if maxSort == nil {
+		return 0
+	}
+	return *maxSort + 1
+}
+
+func (cs *CategoryService) audit(tx *gorm.DB, actorID uint, action string, categoryID uint, meta map[string]interface{}) error {
+	data, _ := json.Marshal(meta)
+	return tx.Create(&models.AuditLog{
+		Actor:    models.UserActor(actorID),
+		Action:   action,
+		Entity:   "category",
+		EntityID: categoryID,
+		Meta:     data,
+	}).Error
+}
+
+// Request DTOs
+type CreateCategoryRequest struct {
+	Name             string                 `json:"name" binding:"required,max=100"`
+	ParentID         *uint                  `json:"parent_id"`
+	SEOSlug          *string                `json:"seo_slug" binding:"omitempty,max=191"`
+	AttributesSchema *models.CategorySchema `json:"attributes_schema"`
+}
+
+type UpdateCategoryRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=1,max=100"`
+	SEOSlug *string `json:"seo_slug" binding:"omitempty,max=191"`
+}
+
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+type ReorderCategoriesRequest struct {
+	ParentID    *uint  `json:"parent_id"`
+	CategoryIDs []uint `json:"category_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+	sellerservices "gocom/main/internal/seller/services"
+)
+
+var ErrKYCNotPending = errors.New("document is not awaiting review")
+
+type KYCService struct {
+	DB    *gorm.DB
+	Store storage.ObjectStore
+}
+
+func NewKYCService() *KYCService {
+	return &KYCService{
+		DB:    db.GetDB(),
+		Store: storage.GetStore(),
+	}
+}
+
+// List KYC documents for review, oldest first
+func (ks *KYCService) ListQueue(filters KYCFilters) ([]models.KYC, int64, error) {
+	var documents []models.KYC
+	var total int64
+
+	query := ks.DB.Model(&models.KYC{}).Where("status = ?", filters.Status)
+
+	// Apply filters
+	if filters.SellerID != nil {
+		query = query.Where("seller_id = ?", *filters.SellerID)
+	}
+	if filters.Type != "" {
+		query = query.Where("type = ?", filters.Type)
+	}
+
+	// Get total count
+	query.Count(&total)
+
+	// Apply pagination
+	offset := (filters.Page - 1) * filters.Limit
+	err := query.
+		Offset(offset).
+		Limit(filters.Limit).
+		Order("created_at ASC").
+		Find(&documents).Error
+
+	return documents, total, err
+}
+
+// Get a KYC document with a time-limited download link
+func (ks *KYCService) GetDocument(documentID uint) (*sellerservices.KYCDocumentResponse, error) {
+	var document models.KYC
+	if err := ks.DB.First(&document, documentID).Error; err != nil {
+		return nil, err
+	}
+
+	return sellerservices.NewKYCDocumentResponse(ks.Store, &document)
+}
+
+// Approve a pending KYC document, superseding any approved one of the
+// same type
+func (ks *KYCService) ApproveDocument(documentID, actorID uint, remarks string) (*models.KYC, error) {
+	return ks.review(documentID, actorID, models.KYCStatusApproved, "kyc.approve", remarks)
+}
+
+// Reject a pending KYC document with remarks for the seller
+func (ks *KYCService) RejectDocument(documentID, actorID uint, remarks string) (*models.KYC, error) {
+	return ks.review(documentID, actorID, models.KYCStatusRejected, "kyc.reject", remarks)
+}
+
+func (ks *KYCService) review(documentID, actorID uint, status int, action, remarks string) (*models.KYC, error) {
+	var document models.KYC
+
+	err := ks.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&document, documentID).Error; err != nil {
+			return err
+		}
+		if document.Status != models.KYCStatusPending {
+			return ErrKYCNotPending
+		}
+
+		now := time.Now()
+		if err := tx.Model(&document).Updates(map[string]interface{}{
+			"status":      status,
+			"remarks":     remarks,
+			"reviewed_by": actorID,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		// The new document replaces the one approved before it
+		if status == models.KYCStatusApproved {
+			if err := tx.Model(&models.KYC{}).
+				Where("seller_id = ? AND type = ? AND status = ? AND id <> ?",
+					document.SellerID, document.Type, models.KYCStatusApproved, document.ID).
+				Update("status", models.KYCStatusSuperseded).Error; err != nil {
+				return err
+			}
+		}
+
+		meta, _ := json.Marshal(map[string]interface{}{
+			"seller_id": document.SellerID,
+			"type":      document.Type,
+			"remarks":   remarks,
+		})
+		return tx.Create(&models.AuditLog{
+			Actor:    models.UserActor(actorID),
+			Action:   action,
+			Entity:   "kyc",
+			EntityID: document.ID,
+			Meta:     meta,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// Request DTOs
+type KYCFilters struct {
+	Status   int    `form:"status,default=0"`
+	SellerID *uint  `form:"seller_id"`
+	Type     string `form:"type"`
+	Page     int    `form:"page,default=1"`
+	Limit    int    `form:"limit,default=20"`
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+	sellerservices "gocom/main/internal/seller/services"
+)
+
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrProductNotPending    = errors.New("product is not awaiting review")
+	ErrProductNotRejectable = errors.New("only products awaiting review or published can be rejected")
+	ErrInvalidRejection     = errors.New("every rejection reason needs a known code and a message")
+	ErrProductSeller        = errors.New("product seller is not approved")
+	ErrKeywordExists        = errors.New("keyword is already prohibited")
+	ErrKeywordInvalid       = errors.New("keyword must contain a letter or digit")
+	ErrBrandExists          = errors.New("brand is already restricted")
+	ErrRestrictionNotFound  = errors.New("moderation rule not found")
+)
+
+type ModerationService struct {
+	DB                *gorm.DB
+	Store             storage.ObjectStore
+	ModerationService *sellerservices.ModerationService
+}
+
+func NewModerationService() *ModerationService {
+	return &ModerationService{
+		DB:                db.GetDB(),
+		Store:             storage.GetStore(),
+		ModerationService: sellerservices.NewModerationService(),
+	}
+}
+
+// List products awaiting review, longest waiting first
+func (ms *ModerationService) ListQueue(filters ModerationFilters) ([]models.Product, int64, error) {
+	var products []models.Product
+	var total int64
+
+	query := ms.DB.Model(&models.Product{}).Where("status = ?", models.ProductStatusPendingReview)
+
+	// Apply filters
+	if filters.SellerID != nil {
+		query = query.Where("seller_id = ?", *filters.SellerID)
+	}
+	if filters.CategoryID != nil {
+		query = query.Where("category_id = ?", *filters.CategoryID)
+	}
+
+	// Get total count
+	query.Count(&total)
+
+	// Apply pagination
+	offset := (filters.Page - 1) * filters.Limit
+	err := query.
+		Preload("Category").
+		Preload("Seller").
+		Offset(offset).
+		Limit(filters.Limit).
+		Order("updated_at ASC").
+		Find(&products).Error
+
+	return products, total, err
+}
+
+// Get a product with everything a moderator needs to decide on it
+func (ms *ModerationService) GetProduct(productID uint) (*ModerationDetail, error) {
+	var product models.Product
+	err := ms.DB.
+		Preload("Category").
+		Preload("Seller").
+		Preload("SKUs").
+		Preload("Media").
+		First(&product, productID).Error
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	for i := range product.Media {
+		if err := storage.ResolveMediaLinks(ms.Store, &product.Media[i], config.AppConfig.MediaURLExpiry); err != nil {
+			return nil, err
+		}
+	}
+
+	history, err := ms.ModerationService.History(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ModerationDetail{Product: product, History: history}, nil
+}
+
+// Approve a product awaiting review, publishing it
+func (ms *ModerationService) ApproveProduct(productID, actorID uint, note string) (*models.Product, error) {
+	var product models.Product
+
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Seller").First(&product, productID).Error; err != nil {
+			return ErrProductNotFound
+		}
+		if product.Status != models.ProductStatusPendingReview {
+			return ErrProductNotPending
+		}
+		if product.Seller.Status != models.SellerStatusApproved {
+			return ErrProductSeller
+		}
+
+		return ms.ModerationService.Transition(tx, &product, models.ProductStatusPublished,
+			models.ModerationActionApprove, &actorID, nil, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// Reject a product awaiting review, or take down a published one, with
+// structured reasons the seller can act on
+func (ms *ModerationService) RejectProduct(productID, actorID uint, req *RejectProductRequest) (*models.Product, error) {
+	for _, reason := range req.Reasons {
+		if !models.IsValidRejectionCode(reason.Code) || strings.TrimSpace(reason.Message) == "" {
+			return nil, ErrInvalidRejection
+		}
+	}
+
+	var product models.Product
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&product, productID).Error; err != nil {
+			return ErrProductNotFound
+		}
+		if product.Status != models.ProductStatusPendingReview && product.Status != models.ProductStatusPublished {
+			return ErrProductNotRejectable
+		}
+
+		return ms.ModerationService.Transition(tx, &product, models.ProductStatusRejected,
+			models.ModerationActionReject, &actorID, req.Reasons, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// List prohibited keywords
+func (ms *ModerationService) ListKeywords() ([]models.ProhibitedKeyword, error) {
+	var keywords []models.ProhibitedKeyword
+	err := ms.DB.Order("keyword ASC").Find(&keywords).Error
+	return keywords, err
+}
+
+// Prohibit a keyword, matched case-insensitively as a whole word
+func (ms *ModerationService) AddKeyword(keyword string) (*models.ProhibitedKeyword, error) {
+	// Stored the way the matcher reads it, so spacing and punctuation
+	// variants of a keyword are duplicates
+	entry := &models.ProhibitedKeyword{Keyword: search.NormalizeKeyword(keyword)}
+	if entry.Keyword == "" {
+		return nil, ErrKeywordInvalid
+	}
+
+	var count int64
+	ms.DB.Model(&models.ProhibitedKeyword{}).Where("keyword = ?", entry.Keyword).Count(&count)
+	if count > 0 {
+		return nil, ErrKeywordExists
+	}
+
+	if err := ms.DB.Create(entry).Error; err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Remove a prohibited keyword
+func (ms *ModerationService) DeleteKeyword(keywordID uint) error {
+	result := ms.DB.Delete(&models.ProhibitedKeyword{}, keywordID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRestrictionNotFound
+	}
+	return nil
+}
+
+// List restricted brands with their authorised sellers
+func (ms *ModerationService) ListRestrictedBrands() ([]models.RestrictedBrand, error) {
+	var brands []models.RestrictedBrand
+	err := ms.DB.Preload("Authorizations").Order("brand ASC").Find(&brands).Error
+	return brands, err
+}
+
+// Restrict a brand to authorised sellers
+func (ms *ModerationService) AddRestrictedBrand(brand string) (*models.RestrictedBrand, error) {
+	entry := &models.RestrictedBrand{Brand: strings.ToLower(strings.TrimSpace(brand))}
+
+	var count int64
+	ms.DB.Model(&models.RestrictedBrand{}).Where("brand = ?", entry.Brand).Count(&count)
+	if count > 0 {
+		return nil, ErrBrandExists
+	}
+
+	if err := ms.DB.Create(entry).Error; err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Lift a brand restriction together with its authorisations
+func (ms *ModerationService) DeleteRestrictedBrand(brandID uint) error {
+	return ms.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("restricted_brand_id = ?", brandID).Delete(&models.BrandAuthorization{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.RestrictedBrand{}, brandID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRestrictionNotFound
+		}
+		return nil
+	})
+}
+
+// Authorise a seller to list a restricted brand
+func (ms *ModerationService) AuthorizeSeller(brandID, sellerID uint) (*models.BrandAuthorization, error) {
+	if err := ms.DB.First(&models.RestrictedBrand{}, brandID).Error; err != nil {
+		return nil, ErrRestrictionNotFound
+	}
+	if err := ms.DB.First(&models.Seller{}, sellerID).Error; err != nil {
+		return nil, sellerservices.ErrSellerNotFound
+	}
+
+	authorization := &models.BrandAuthorization{RestrictedBrandID: brandID, SellerID: sellerID}
+	err := ms.DB.
+		Where(authorization).
+		FirstOrCreate(authorization).Error
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+// Revoke a seller's authorisation for a restricted brand
+func (ms *ModerationService) RevokeSeller(brandID, sellerID uint) error {
+	result := ms.DB.
+		Where("restricted_brand_id = ? AND seller_id = ?", brandID, sellerID).
+		Delete(&models.BrandAuthorization{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRestrictionNotFound
+	}
+	return nil
+}
+
+// Request DTOs
+type ModerationFilters struct {
+	SellerID   *uint `form:"seller_id"`
+	CategoryID *uint `form:"category_id"`
+	Page       int   `form:"page,default=1"`
+	Limit      int   `form:"limit,default=20"`
+}
+
+type RejectProductRequest struct {
+	Reasons []models.RejectionReason `json:"reasons" binding:"required,min=1,dive"`
+	Note    string                   `json:"note" binding:"max=1000"`
+}
+
+type KeywordRequest struct {
+	Keyword string `json:"keyword" binding:"required,max=191"`
+}
+
+type RestrictedBrandRequest struct {
+	Brand string `json:"brand" binding:"required,max=191"`
+}
+
+type BrandAuthorizationRequest struct {
+	SellerID uint `json:"seller_id" binding:"required"`
+}
+
+// Response DTOs
+type ModerationDetail struct {
+	Product models.Product             `json:"product"`
+	History []models.ProductModeration `json:"history"`
+}
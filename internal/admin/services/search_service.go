@@ -0,0 +1,63 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+type SearchService struct {
+	DB *gorm.DB
+}
+
+func NewSearchService() *SearchService {
+	return &SearchService{
+		DB: db.GetDB(),
+	}
+}
+
+// List buyer searches that found nothing in the period, most frequent
+// first, so the catalog team can fill gaps or add synonyms. A search drops
+// off the report once it finds products again.
+func (ss *SearchService) ListZeroResultQueries(filters ZeroResultFilters) ([]ZeroResultQuery, int64, error) {
+	queries := make([]ZeroResultQuery, 0)
+	var total int64
+
+	grouped := ss.DB.Model(&models.SearchQuery{}).
+		Select("query, COUNT(*) AS searches, MAX(created_at) AS last_searched_at").
+		Where("created_at > ?", time.Now().AddDate(0, 0, -filters.Days)).
+		Group("query").
+		Having("MAX(results) = 0")
+
+	// Get total count
+	if err := ss.DB.Table("(?) AS zero_results", grouped).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	offset := (filters.Page - 1) * filters.Limit
+	err := grouped.
+		Order("searches DESC, last_searched_at DESC").
+		Offset(offset).
+		Limit(filters.Limit).
+		Scan(&queries).Error
+
+	return queries, total, err
+}
+
+// Request DTOs
+type ZeroResultFilters struct {
+	Days  int `form:"days,default=30" binding:"min=1,max=365"`
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=50" binding:"min=1,max=200"`
+}
+
+// Response DTOs
+type ZeroResultQuery struct {
+	Query          string    `json:"query"`
+	Searches       int       `json:"searches"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+	sellerservices "gocom/main/internal/seller/services"
+)
+
+var (
+	ErrSellerNotPending = errors.New("seller is not awaiting review")
+	ErrSellerIncomplete = errors.New("seller onboarding checklist is incomplete")
+)
+
+type SellerService struct {
+	DB                *gorm.DB
+	OnboardingService *sellerservices.OnboardingService
+}
+
+func NewSellerService() *SellerService {
+	return &SellerService{
+		DB:                db.GetDB(),
+		OnboardingService: sellerservices.NewOnboardingService(),
+	}
+}
+
+// List sellers for review
+func (ss *SellerService) ListSellers(filters SellerFilters) ([]models.Seller, int64, error) {
+	var sellers []models.Seller
+	var total int64
+
+	query := ss.DB.Model(&models.Seller{})
+
+	// Apply filters
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.Search != "" {
+		like := "%" + filters.Search + "%"
+		query = query.Where("legal_name LIKE ? OR display_name LIKE ? OR gstin = ? OR pan = ?",
+			like, like, filters.Search, filters.Search)
+	}
+
+	// Get total count
+	query.Count(&total)
+
+	// Apply pagination
+	offset := (filters.Page - 1) * filters.Limit
+	err := query.
+		Offset(offset).
+		Limit(filters.Limit).
+		Order("created_at DESC").
+		Find(&sellers).Error
+
+	return sellers, total, err
+}
+
+// Get seller with team members
+func (ss *SellerService) GetSeller(sellerID uint) (*SellerDetail, error) {
+	var seller models.Seller
+	if err := ss.DB.First(&seller, sellerID).Error; err != nil {
+		return nil, err
+	}
+
+	var members []models.SellerUser
+	if err := ss.DB.Where("seller_id = ?", sellerID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return &SellerDetail{Seller: seller, Members: members}, nil
+}
+
+// Approve a pending seller whose onboarding checklist is complete
+func (ss *SellerService) ApproveSeller(sellerID, actorID uint, remarks string) (*models.Seller, error) {
+	checklist, err := ss.OnboardingService.GetChecklist(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if checklist.Status != models.SellerStatusPending {
+		return nil, ErrSellerNotPending
+	}
+	if !checklist.Complete {
+		return nil, ErrSellerIncomplete
+	}
+
+	return ss.transition(sellerID, actorID, models.SellerStatusApproved, "seller.approve", remarks)
+}
+
+// Reject a pending seller, or revoke an approved one, with remarks
+func (ss *SellerService) RejectSeller(sellerID, actorID uint, remarks string) (*models.Seller, error) {
+	var seller models.Seller
+	if err := ss.DB.First(&seller, sellerID).Error; err != nil {
+		return nil, err
+	}
+	if seller.Status == models.SellerStatusRejected {
+		return nil, ErrSellerNotPending
+	}
+
+	return ss.transition(sellerID, actorID, models.SellerStatusRejected, "seller.reject", remarks)
+}
+
+// Move a seller to a new status and record who did it and why
+func (ss *SellerService) transition(sellerID, actorID uint, status int, action, remarks string) (*models.Seller, error) {
+	var seller models.Seller
+
+	err := ss.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&seller, sellerID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&seller).Updates(map[string]interface{}{
+			"status":         status,
+			"status_remarks": remarks,
+			"reviewed_by":    actorID,
+			"reviewed_at":    now,
+		}).Error; err != nil {
+			return err
+		}
+
+		meta, _ := json.Marshal(map[string]interface{}{"remarks": remarks})
+		return tx.Create(&models.AuditLog{
+			Actor:    models.UserActor(actorID),
+			Action:   action,
+			Entity:   "seller",
+			EntityID: seller.ID,
+			Meta:     meta,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &seller, nil
+}
+
+// Request DTOs
+type SellerFilters struct {
+	Status *int   `form:"status"`
+	Search string `form:"search"`
+	Page   int    `form:"page,default=1"`
+	Limit  int    `form:"limit,default=20"`
+}
+
+type ReviewRequest struct {
+	Remarks string `json:"remarks" binding:"max=1000"`
+}
+
+type RejectRequest struct {
+	Remarks string `json:"remarks" binding:"required,max=1000"`
+}
+
+// Response DTOs
+type SellerDetail struct {
+	Seller  models.Seller       `json:"seller"`
+	Members []models.SellerUser `json:"members"`
+}
@@ -1 +1,201 @@
-package auth
\ No newline at end of file
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/errors"
+)
+
+// PrincipalKey is the gin.Context key holding the authenticated *Claims
+const PrincipalKey = "auth.principal"
+
+// Audience of tokens issued between password and second-factor checks
+const mfaAudience = "mfa"
+
+// SellerMembership is a seller the user belongs to and their role in it
+type SellerMembership struct {
+	SellerID uint   `json:"seller_id"`
+	Role     string `json:"role"`
+}
+
+// Claims carried by access tokens
+type Claims struct {
+	UserID  uint               `json:"uid"`
+	Roles   []string           `json:"roles,omitempty"`
+	Sellers []SellerMembership `json:"sellers,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasRole reports whether the principal holds the given platform role
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SellerIDs returns the IDs of all sellers the principal is a member of
+func (c *Claims) SellerIDs() []uint {
+	ids := make([]uint, 0, len(c.Sellers))
+	for _, s := range c.Sellers {
+		ids = append(ids, s.SellerID)
+	}
+	return ids
+}
+
+// SellerRole returns the principal's role in the given seller
+func (c *Claims) SellerRole(sellerID uint) (string, bool) {
+	for _, s := range c.Sellers {
+		if s.SellerID == sellerID {
+			return s.Role, true
+		}
+	}
+	return "", false
+}
+
+// GenerateToken signs an access token for the user
+func GenerateToken(userID uint, roles []string, sellers []SellerMembership) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:  userID,
+		Roles:   roles,
+		Sellers: sellers,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprintf("%d", userID),
+			Issuer:    config.AppConfig.JWTIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(config.AppConfig.JWTAccessTTL)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+}
+
+// MFAClaims are carried by short-lived "mfa pending" tokens. They use a
+// different user claim than Claims so they can never pass as access tokens.
+type MFAClaims struct {
+	PendingUserID uint `json:"mfa_uid"`
+	jwt.RegisteredClaims
+}
+
+// GenerateMFAToken signs a token proving the user passed the password step
+func GenerateMFAToken(userID uint) (string, error) {
+	now := time.Now()
+	claims := &MFAClaims{
+		PendingUserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprintf("%d", userID),
+			Issuer:    config.AppConfig.JWTIssuer,
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(config.AppConfig.MFATokenTTL)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+}
+
+// ValidateMFAToken parses an "mfa pending" token and returns the user ID
+func ValidateMFAToken(tokenString string) (uint, error) {
+	claims := &MFAClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(config.AppConfig.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(config.AppConfig.JWTIssuer),
+		jwt.WithAudience(mfaAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if !token.Valid || claims.PendingUserID == 0 {
+		return 0, fmt.Errorf("invalid token")
+	}
+
+	return claims.PendingUserID, nil
+}
+
+// ValidateToken parses and verifies an access token
+func ValidateToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(config.AppConfig.JWTSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(config.AppConfig.JWTIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.UserID == 0 {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return claims, nil
+}
+
+// AuthMiddleware rejects requests without a valid Bearer token and puts
+// the authenticated principal on the context
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			c.AbortWithError(errors.ErrUnauthorized.Code, errors.ErrUnauthorized)
+			return
+		}
+
+		claims, err := ValidateToken(tokenString)
+		if err != nil {
+			c.AbortWithError(errors.ErrUnauthorized.Code, errors.ErrUnauthorized)
+			return
+		}
+
+		c.Set(PrincipalKey, claims)
+		c.Next()
+	}
+}
+
+// RequireRole rejects principals that hold none of the given platform roles.
+// Must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			c.AbortWithError(errors.ErrUnauthorized.Code, errors.ErrUnauthorized)
+			return
+		}
+
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithError(errors.ErrForbidden.Code, errors.ErrForbidden)
+	}
+}
+
+// GetPrincipal returns the authenticated principal set by AuthMiddleware
+func GetPrincipal(c *gin.Context) (*Claims, bool) {
+	value, exists := c.Get(PrincipalKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
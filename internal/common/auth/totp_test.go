@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// The SHA-1 secret from RFC 6238 appendix B, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	// RFC 6238 test vectors, cut to the last six digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/totpPeriod {
+			t.Errorf("ValidateTOTP(%q at %d) = %d, %v; want %d, true", tt.code, tt.unix, step, ok, tt.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	issued := time.Unix(1111111109, 0) // step 37037036, code "081804"
+
+	tests := []struct {
+		name string
+		now  time.Time
+		code string
+		want bool
+	}{
+		{"one step late", issued.Add(totpPeriod * time.Second), "081804", true},
+		{"one step early", issued.Add(-totpPeriod * time.Second), "081804", true},
+		{"two steps late", issued.Add(2 * totpPeriod * time.Second), "081804", false},
+		{"wrong code", issued, "081805", false},
+		{"short code", issued, "81804", false},
+		{"bad secret", issued, "081804", false},
+	}
+	for _, tt := range tests {
+		secret := rfcSecret
+		if tt.name == "bad secret" {
+			secret = "not base32!"
+		}
+		if _, ok := ValidateTOTP(secret, tt.code, tt.now); ok != tt.want {
+			t.Errorf("%s: ValidateTOTP = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -21,21 +23,77 @@ type Config struct {
 	RedisPort     string
 	RedisPassword string
 
+	// Object storage, "minio" or "local"
+	StorageBackend    string
+	StorageLocalPath  string
+	StoragePublicURL  string
+	StorageSigningKey string
+
 	// MinIO
 	MinIOEndpoint   string
 	MinIOAccessKey  string
 	MinIOSecretKey  string
 	MinIOUseSSL     bool
 
+	// Uploads
+	KYCMaxUploadBytes  int64
+	MediaMaxImageBytes int64
+	MediaMaxVideoBytes int64
+	PresignExpiry      time.Duration
+	MediaURLExpiry     time.Duration // Product media links in API responses
+	TempUploadMaxAge   time.Duration
+	UploadSweepEvery   time.Duration
+
+	// Catalog
+	CategoryTreeCacheTTL time.Duration
+	ImportMaxBytes       int64
+	ImportMaxRows        int
+	CatalogJobPollEvery  time.Duration // How often import and export workers look for jobs
+
+	// Marketplace search, "index" or "mysql"
+	SearchBackend      string
+	SearchIndexPath    string // Snapshot file of the index, empty keeps it in memory
+	SearchSynonymsFile string // One comma-separated synonym group per line
+	SearchSyncEvery    time.Duration
+
+	// Search events are deleted after this long; an index that has not
+	// caught up by then is rebuilt
+	SearchEventRetention time.Duration
+
+	// Logged buyer searches are kept this long, for suggestions and the
+	// zero-result report
+	SearchQueryRetention  time.Duration
+	SearchQueryPruneEvery time.Duration
+	SearchClientKey       string // Keys the client hashes, derived from JWTSecret when empty
+
+	// Product image rules
+	ImageMinWidth       int
+	ImageMinHeight      int
+	ImageMaxPixels      int
+	ImageMaxAspectRatio float64
+
 	// JWT
-	JWTSecret string
+	JWTSecret     string
+	JWTIssuer     string
+	JWTAccessTTL  time.Duration
+	JWTRefreshTTL time.Duration
+
+	// Login lockout
+	LoginMaxAttempts int
+	LoginLockout     time.Duration
+
+	// Two-factor authentication
+	TwoFAIssuer        string
+	TwoFAEncryptionKey string
+	MFATokenTTL        time.Duration
 
 	// Payment Gateway
 	RazorpayKeyID     string
 	RazorpayKeySecret string
 
 	// Server
-	ServerPort string
+	ServerPort     string
+	TrustedProxies []string // Proxies whose X-Forwarded-For is believed, none by default
 
 	// Logging
 	GinMode  string
@@ -53,6 +111,20 @@ func LoadConfig() {
 	// Parse boolean values
 	minioUseSSL, _ := strconv.ParseBool(getEnv("MINIO_USE_SSL", "false"))
 
+	// Parse integer values
+	loginMaxAttempts, _ := strconv.Atoi(getEnv("LOGIN_MAX_ATTEMPTS", "5"))
+	kycMaxUploadBytes, _ := strconv.ParseInt(getEnv("KYC_MAX_UPLOAD_BYTES", "5242880"), 10, 64)
+	mediaMaxImageBytes, _ := strconv.ParseInt(getEnv("MEDIA_MAX_IMAGE_BYTES", "10485760"), 10, 64)
+	mediaMaxVideoBytes, _ := strconv.ParseInt(getEnv("MEDIA_MAX_VIDEO_BYTES", "104857600"), 10, 64)
+	importMaxBytes, _ := strconv.ParseInt(getEnv("IMPORT_MAX_BYTES", "20971520"), 10, 64)
+	importMaxRows, _ := strconv.Atoi(getEnv("IMPORT_MAX_ROWS", "10000"))
+	imageMinWidth, _ := strconv.Atoi(getEnv("IMAGE_MIN_WIDTH", "500"))
+	imageMinHeight, _ := strconv.Atoi(getEnv("IMAGE_MIN_HEIGHT", "500"))
+	imageMaxPixels, _ := strconv.Atoi(getEnv("IMAGE_MAX_PIXELS", "40000000"))
+
+	// Parse float values
+	imageMaxAspectRatio, _ := strconv.ParseFloat(getEnv("IMAGE_MAX_ASPECT_RATIO", "2.0"), 64)
+
 	AppConfig = &Config{
 		// Database
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -66,31 +138,91 @@ func LoadConfig() {
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", "redis_pass_2024"),
 
+		// Object storage
+		StorageBackend:    getEnv("STORAGE_BACKEND", "minio"),
+		StorageLocalPath:  getEnv("STORAGE_LOCAL_PATH", "./data/storage"),
+		StoragePublicURL:  getEnv("STORAGE_PUBLIC_URL", "http://localhost:"+getEnv("SERVER_PORT", "8080")),
+		StorageSigningKey: getEnv("STORAGE_SIGNING_KEY", ""),
+
 		// MinIO
 		MinIOEndpoint:  getEnv("MINIO_ENDPOINT", "localhost:9000"),
 		MinIOAccessKey: getEnv("MINIO_ACCESS_KEY", "minioadmin"),
 		MinIOSecretKey: getEnv("MINIO_SECRET_KEY", "minioadmin123"),
 		MinIOUseSSL:    minioUseSSL,
 
+		// Uploads
+		KYCMaxUploadBytes:  kycMaxUploadBytes,
+		MediaMaxImageBytes: mediaMaxImageBytes,
+		MediaMaxVideoBytes: mediaMaxVideoBytes,
+		PresignExpiry:      getEnvDuration("PRESIGN_EXPIRY", "15m"),
+		MediaURLExpiry:     getEnvDuration("MEDIA_URL_EXPIRY", "6h"),
+		TempUploadMaxAge:   getEnvDuration("TEMP_UPLOAD_MAX_AGE", "24h"),
+		UploadSweepEvery:   getEnvDuration("UPLOAD_SWEEP_INTERVAL", "1h"),
+
+		// Catalog
+		CategoryTreeCacheTTL: getEnvDuration("CATEGORY_TREE_CACHE_TTL", "5m"),
+		ImportMaxBytes:       importMaxBytes,
+		ImportMaxRows:        importMaxRows,
+		CatalogJobPollEvery:  getEnvDuration("CATALOG_JOB_POLL_INTERVAL", "5s"),
+
+		// Marketplace search
+		SearchBackend:      getEnv("SEARCH_BACKEND", "index"),
+		SearchIndexPath:    getEnv("SEARCH_INDEX_PATH", "./data/search/products.idx"),
+		SearchSynonymsFile: getEnv("SEARCH_SYNONYMS_FILE", ""),
+		SearchSyncEvery:    getEnvDuration("SEARCH_SYNC_INTERVAL", "5s"),
+
+		SearchEventRetention: getEnvDuration("SEARCH_EVENT_RETENTION", "168h"),
+
+		SearchQueryRetention:  getEnvDuration("SEARCH_QUERY_RETENTION", "8760h"),
+		SearchQueryPruneEvery: getEnvDuration("SEARCH_QUERY_PRUNE_INTERVAL", "1h"),
+		SearchClientKey:       getEnv("SEARCH_CLIENT_KEY", ""),
+
+		// Product image rules
+		ImageMinWidth:       imageMinWidth,
+		ImageMinHeight:      imageMinHeight,
+		ImageMaxPixels:      imageMaxPixels,
+		ImageMaxAspectRatio: imageMaxAspectRatio,
+
 		// JWT
-		JWTSecret: getEnv("JWT_SECRET", "commerce_jwt_secret_2024"),
+		JWTSecret:     getEnv("JWT_SECRET", ""),
+		JWTIssuer:     getEnv("JWT_ISSUER", "gocom"),
+		JWTAccessTTL:  getEnvDuration("JWT_ACCESS_TTL", "15m"),
+		JWTRefreshTTL: getEnvDuration("JWT_REFRESH_TTL", "720h"),
+
+		// Login lockout
+		LoginMaxAttempts: loginMaxAttempts,
+		LoginLockout:     getEnvDuration("LOGIN_LOCKOUT", "15m"),
+
+		// Two-factor authentication
+		TwoFAIssuer:        getEnv("TWOFA_ISSUER", "GoCom"),
+		TwoFAEncryptionKey: getEnv("TWOFA_ENCRYPTION_KEY", ""),
+		MFATokenTTL:        getEnvDuration("MFA_TOKEN_TTL", "5m"),
 
 		// Payment Gateway
 		RazorpayKeyID:     getEnv("RAZORPAY_KEY_ID", ""),
 		RazorpayKeySecret: getEnv("RAZORPAY_KEY_SECRET", ""),
 
 		// Server
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+		ServerPort:     getEnv("SERVER_PORT", "8080"),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 
 		// Logging
 		GinMode:  getEnv("GIN_MODE", "debug"),
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 
+	// Keys that protect stored or issued data have no default
+	requireSecret("JWT_SECRET", AppConfig.JWTSecret, "commerce_jwt_secret_2024")
+	requireSecret("TWOFA_ENCRYPTION_KEY", AppConfig.TwoFAEncryptionKey, "commerce_2fa_key_2024")
+	if AppConfig.StorageBackend == "local" {
+		// Signs the links the local backend serves itself
+		requireSecret("STORAGE_SIGNING_KEY", AppConfig.StorageSigningKey, "commerce_storage_key_2024")
+	}
+
 	log.Printf("✅ Configuration loaded successfully")
 	log.Printf("📦 Database: %s:%s/%s", AppConfig.DBHost, AppConfig.DBPort, AppConfig.DBName)
 	log.Printf("🔴 Redis: %s:%s", AppConfig.RedisHost, AppConfig.RedisPort)
-	log.Printf("📁 MinIO: %s", AppConfig.MinIOEndpoint)
+	log.Printf("📁 Storage: %s (MinIO: %s)", AppConfig.StorageBackend, AppConfig.MinIOEndpoint)
 	log.Printf("🚀 Server will run on port: %s", AppConfig.ServerPort)
 }
 
@@ -101,6 +233,33 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// requireSecret stops startup when a secret is unset or still the value
+// that used to be shipped as its default
+func requireSecret(key, value, oldDefault string) {
+	if value == "" || value == oldDefault {
+		log.Fatalf("%s must be set to a private value", key)
+	}
+}
+
+// getEnvList reads a comma-separated list, empty when unset
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvDuration(key, defaultValue string) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, defaultValue))
+	if err != nil {
+		log.Fatalf("Invalid duration for %s: %v", key, err)
+	}
+	return value
+}
+
 // Helper functions for specific configs
 func GetDatabaseDSN() string {
 	return AppConfig.DBUser + ":" + AppConfig.DBPassword + 
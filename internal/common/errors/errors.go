@@ -6,9 +6,10 @@ import (
 )
 
 type APIError struct {
-    Code    int    `json:"code"`
-    Message string `json:"message"`
-    Details string `json:"details,omitempty"`
+    Code    int               `json:"code"`
+    Message string            `json:"message"`
+    Details string            `json:"details,omitempty"`
+    Fields  map[string]string `json:"fields,omitempty"`
 }
 
 func NewAPIError(code int, message, details string) *APIError {
@@ -19,6 +20,15 @@ func NewAPIError(code int, message, details string) *APIError {
     }
 }
 
+// NewValidationError is ErrValidation with per-field messages
+func NewValidationError(fields map[string]string) *APIError {
+    return &APIError{
+        Code:    ErrValidation.Code,
+        Message: ErrValidation.Message,
+        Fields:  fields,
+    }
+}
+
 func (e *APIError) Error() string {
     return e.Message
 }
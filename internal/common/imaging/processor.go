@@ -0,0 +1,196 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	// Decoders for the formats sellers may upload
+	_ "image/gif"
+	_ "golang.org/x/image/webp"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrNotAnImage    = errors.New("file is not a decodable image")
+	ErrTooSmall      = errors.New("image resolution is below the minimum")
+	ErrTooManyPixels = errors.New("image resolution is above the maximum")
+	ErrAspectRatio   = errors.New("image aspect ratio is outside the allowed range")
+)
+
+// Variant is a resized copy generated for every product image, bounded by
+// MaxSide on its longest edge
+type Variant struct {
+	Name    string
+	MaxSide int
+}
+
+// Variants generated for every product image
+var Variants = []Variant{
+	{Name: "thumbnail", MaxSide: 150},
+	{Name: "listing", MaxSide: 400},
+	{Name: "zoom", MaxSide: 1600},
+}
+
+// WebPVariant names the WebP rendition, encoded at zoom size
+const WebPVariant = "webp"
+
+// Rules an uploaded image must satisfy
+type Rules struct {
+	MinWidth       int
+	MinHeight      int
+	MaxPixels      int
+	MaxAspectRatio float64 // longest side / shortest side
+}
+
+// Rendition is one encoded output image
+type Rendition struct {
+	Name        string
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Result of processing an uploaded image
+type Result struct {
+	Format     string // jpeg, png, gif or webp
+	Width      int
+	Height     int
+	Renditions []Rendition
+}
+
+// Process validates an uploaded image and renders its size variants plus
+// a WebP rendition. The original bytes are left for the caller to store.
+func Process(data []byte, rules Rules) (*Result, error) {
+	// Check dimensions from the header before decoding the full bitmap
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotAnImage
+	}
+	if err := checkDimensions(cfg.Width, cfg.Height, rules); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotAnImage
+	}
+
+	result := &Result{
+		Format: format,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+
+	// Variants keep transparency as PNG, everything else becomes JPEG
+	encode, contentType, ext := encodeJPEG, "image/jpeg", ".jpg"
+	if !isOpaque(img) {
+		encode, contentType, ext = encodePNG, "image/png", ".png"
+	}
+
+	var zoom image.Image
+	for _, variant := range Variants {
+		resized := fit(img, variant.MaxSide)
+		if variant.Name == "zoom" {
+			zoom = resized
+		}
+
+		buf := &bytes.Buffer{}
+		if err := encode(buf, resized); err != nil {
+			return nil, fmt.Errorf("encode %s variant: %w", variant.Name, err)
+		}
+
+		result.Renditions = append(result.Renditions, Rendition{
+			Name:        variant.Name,
+			ContentType: contentType,
+			Ext:         ext,
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	if err := nativewebp.Encode(buf, zoom, nil); err != nil {
+		return nil, fmt.Errorf("encode webp rendition: %w", err)
+	}
+	result.Renditions = append(result.Renditions, Rendition{
+		Name:        WebPVariant,
+		ContentType: "image/webp",
+		Ext:         ".webp",
+		Width:       zoom.Bounds().Dx(),
+		Height:      zoom.Bounds().Dy(),
+		Data:        buf.Bytes(),
+	})
+
+	return result, nil
+}
+
+func checkDimensions(width, height int, rules Rules) error {
+	if width < rules.MinWidth || height < rules.MinHeight {
+		return fmt.Errorf("%w: got %dx%d, need at least %dx%d", ErrTooSmall, width, height, rules.MinWidth, rules.MinHeight)
+	}
+	if rules.MaxPixels > 0 && width*height > rules.MaxPixels {
+		return ErrTooManyPixels
+	}
+
+	long, short := width, height
+	if short > long {
+		long, short = short, long
+	}
+	if rules.MaxAspectRatio > 0 && float64(long)/float64(short) > rules.MaxAspectRatio {
+		return fmt.Errorf("%w: %dx%d exceeds %.1f:1", ErrAspectRatio, width, height, rules.MaxAspectRatio)
+	}
+
+	return nil
+}
+
+// Scale img down so its longest side is at most maxSide, never upscaling
+func fit(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = height * maxSide / width
+		width = maxSide
+	} else {
+		width = width * maxSide / height
+		height = maxSide
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
+
+func encodeJPEG(buf *bytes.Buffer, img image.Image) error {
+	return jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+}
+
+func encodePNG(buf *bytes.Buffer, img image.Image) error {
+	return png.Encode(buf, img)
+}
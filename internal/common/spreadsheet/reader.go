@@ -0,0 +1,331 @@
+// Package spreadsheet reads tabular uploads (CSV and XLSX) into rows of
+// strings so importers can treat both formats alike
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// MaxXLSXUncompressedBytes caps how much a workbook may expand to, so a
+// small upload cannot unzip into gigabytes of XML
+const MaxXLSXUncompressedBytes = 200 << 20
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported spreadsheet format, use csv or xlsx")
+	ErrInvalidXLSX       = errors.New("file is not a valid xlsx workbook")
+	ErrXLSXTooLarge      = errors.New("xlsx workbook is too large once uncompressed")
+)
+
+// FormatFromName derives the format from a file name's extension
+func FormatFromName(name string) (string, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Row is a row of cells with its 1-based line number in the file (the row
+// number for XLSX), so errors can point the user at the right place
+type Row struct {
+	Line  int
+	Cells []string
+}
+
+// Read returns the non-blank rows of data, which is in the given format.
+// For XLSX only the first worksheet is read.
+func Read(format string, data []byte) ([]Row, error) {
+	var rows []Row
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(data)
+	case FormatXLSX:
+		rows, err = readXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nonBlank := rows[:0]
+	for _, row := range rows {
+		if !isEmpty(row.Cells) {
+			nonBlank = append(nonBlank, row)
+		}
+	}
+	return nonBlank, nil
+}
+
+// isEmpty reports whether every cell of a row is blank
+func isEmpty(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readCSV(data []byte) ([]Row, error) {
+	// Spreadsheet programs like to prepend a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// The reader skips empty lines, so ask it where the record started
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{Line: line, Cells: record})
+	}
+}
+
+// XLSX part layouts, reduced to what is needed to read cell values
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is either a plain <t> or rich text runs <r><t>
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([]Row, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	var uncompressed uint64
+	for _, f := range archive.File {
+		files[f.Name] = f
+		uncompressed += f.UncompressedSize64
+	}
+	if uncompressed > MaxXLSXUncompressedBytes {
+		return nil, ErrXLSXTooLarge
+	}
+	budget := new(int64)
+	*budget = MaxXLSXUncompressedBytes
+
+	sheetPath, err := firstSheetPath(files, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(f, &shared, budget); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidXLSX
+	}
+	var sheet xlsxSheet
+	if err := decodeXML(sheetFile, &sheet, budget); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		// Rows and cells may be sparse; their references say where they go
+		line := i + 1
+		if row.R > 0 {
+			line = row.R
+		}
+
+		var cells []string
+		for j, cell := range row.Cells {
+			column := j
+			if cell.R != "" {
+				if column, err = columnIndex(cell.R); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.T {
+			case "s":
+				n, err := strconv.Atoi(cell.V)
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, ErrInvalidXLSX
+				}
+				cells[column] = shared.Items[n].String()
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			case "b":
+				cells[column] = strconv.FormatBool(cell.V == "1")
+			default:
+				cells[column] = cell.V
+			}
+		}
+		rows = append(rows, Row{Line: line, Cells: cells})
+	}
+
+	return rows, nil
+}
+
+// Resolve the first worksheet through the workbook relationships, falling
+// back to the conventional location
+func firstSheetPath(files map[string]*zip.File, budget *int64) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidXLSX
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXML(workbookFile, &workbook, budget); err != nil {
+		return "", err
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok || len(workbook.Sheets) == 0 {
+		return fallback, nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXML(relsFile, &rels, budget); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXML decodes a zip entry, counting what it expands to against the
+// budget shared by all entries of the workbook
+func decodeXML(f *zip.File, v interface{}, budget *int64) error {
+	reader, err := f.Open()
+	if err != nil {
+		return ErrInvalidXLSX
+	}
+	defer reader.Close()
+
+	// The declared sizes are checked up front but can lie, so reading also
+	// stops once the workbook expands past the cap
+	limited := &limitedReader{reader: reader, remaining: budget}
+	if err := xml.NewDecoder(limited).Decode(v); err != nil && err != io.EOF {
+		if errors.Is(err, ErrXLSXTooLarge) {
+			return ErrXLSXTooLarge
+		}
+		return fmt.Errorf("%w: %s: %v", ErrInvalidXLSX, f.Name, err)
+	}
+	return nil
+}
+
+// limitedReader is io.LimitReader failing with ErrXLSXTooLarge instead of
+// a silent EOF, which would read as a truncated workbook
+type limitedReader struct {
+	reader    io.Reader
+	remaining *int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if *l.remaining <= 0 {
+		return 0, ErrXLSXTooLarge
+	}
+	if int64(len(p)) > *l.remaining {
+		p = p[:*l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	*l.remaining -= int64(n)
+	return n, err
+}
+
+// columnIndex converts the letters of a cell reference such as "AB12" to a
+// zero-based column index
+func columnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("%w: bad cell reference %q", ErrInvalidXLSX, ref)
+	}
+	return column - 1, nil
+}
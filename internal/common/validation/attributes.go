@@ -0,0 +1,186 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gocom/main/internal/models"
+)
+
+// FieldErrors maps a field path such as "skus[0].attributes.size" to what
+// is wrong with it
+type FieldErrors map[string]string
+
+func (fe FieldErrors) Error() string {
+	fields := make([]string, 0, len(fe))
+	for field := range fe {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return "validation failed for " + strings.Join(fields, ", ")
+}
+
+// Merge adds all errors of other to fe
+func (fe FieldErrors) Merge(other FieldErrors) {
+	for field, message := range other {
+		fe[field] = message
+	}
+}
+
+// ValidateAttributes checks attributes against the product-level (variant
+// false) or SKU-level (variant true) definitions of a category schema.
+// prefix is prepended to every field path in the result.
+func ValidateAttributes(schema *models.CategorySchema, attrs models.Attributes, variant bool, prefix string) FieldErrors {
+	errs := FieldErrors{}
+
+	// A category without a schema accepts any attributes
+	if len(schema.Attributes) == 0 {
+		return errs
+	}
+
+	known := make(map[string]bool)
+	for _, def := range schema.Attributes {
+		known[def.Name] = def.Variant == variant
+		if def.Variant != variant {
+			continue
+		}
+
+		field := prefix + def.Name
+		value, ok := attrs[def.Name]
+		if !ok || value == nil || value == "" {
+			if def.Required {
+				errs[field] = "is required"
+			}
+			continue
+		}
+
+		if message := validateAttribute(def, value); message != "" {
+			errs[field] = message
+		}
+	}
+
+	for name := range attrs {
+		level, ok := known[name]
+		switch {
+		case !ok:
+			errs[prefix+name] = "is not an attribute of this category"
+		case !level && variant:
+			errs[prefix+name] = "is set on the product, not per SKU"
+		case !level:
+			errs[prefix+name] = "is set per SKU, not on the product"
+		}
+	}
+
+	return errs
+}
+
+// ValidateSchema checks that a category schema is well-formed before it is
+// stored: unique names, known types, options for selects, valid patterns
+func ValidateSchema(schema *models.CategorySchema) FieldErrors {
+	errs := FieldErrors{}
+	seen := make(map[string]bool)
+
+	for i, def := range schema.Attributes {
+		field := fmt.Sprintf("attributes[%d]", i)
+		switch {
+		case def.Name == "":
+			errs[field+".name"] = "is required"
+		case seen[def.Name]:
+			errs[field+".name"] = fmt.Sprintf("%q is defined more than once", def.Name)
+		}
+		seen[def.Name] = true
+
+		switch def.Type {
+		case models.AttributeTypeText, models.AttributeTypeNumber, models.AttributeTypeBoolean:
+		case models.AttributeTypeSelect:
+			if len(def.Options) == 0 {
+				errs[field+".options"] = "are required for select attributes"
+			}
+		default:
+			errs[field+".type"] = "must be one of: text, select, number, boolean"
+		}
+
+		if def.Validation != "" {
+			if _, err := compilePattern(def.Validation); err != nil {
+				errs[field+".validation"] = "is not a valid regular expression"
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateAttribute(def models.AttributeDefinition, value interface{}) string {
+	var text string
+	switch def.Type {
+	case models.AttributeTypeText:
+		s, ok := value.(string)
+		if !ok {
+			return "must be text"
+		}
+		text = s
+	case models.AttributeTypeSelect:
+		s, ok := value.(string)
+		if !ok || !contains(def.Options, s) {
+			return fmt.Sprintf("must be one of: %s", strings.Join(def.Options, ", "))
+		}
+		text = s
+	case models.AttributeTypeNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return "must be a number"
+		}
+		text = strconv.FormatFloat(n, 'f', -1, 64)
+	case models.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be true or false"
+		}
+		return ""
+	default:
+		return fmt.Sprintf("has unsupported type %q in the category schema", def.Type)
+	}
+
+	if def.Validation != "" {
+		pattern, err := compilePattern(def.Validation)
+		if err != nil {
+			return "has an invalid validation pattern in the category schema"
+		}
+		if !pattern.MatchString(text) {
+			return fmt.Sprintf("must match %s", def.Validation)
+		}
+	}
+
+	return ""
+}
+
+// Compiled validation patterns by source. Patterns come from category
+// schemas, so there are few of them and each is compiled once.
+var patterns sync.Map
+
+// compilePattern compiles a schema validation pattern so that it has to
+// match the whole value, not just part of it
+func compilePattern(source string) (*regexp.Regexp, error) {
+	if pattern, ok := patterns.Load(source); ok {
+		return pattern.(*regexp.Regexp), nil
+	}
+
+	pattern, err := regexp.Compile(`^(?:` + source + `)$`)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(source, pattern)
+	return pattern, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"testing"
+
+	"gocom/main/internal/models"
+)
+
+func TestValidateAttributesPattern(t *testing.T) {
+	schema := &models.CategorySchema{Attributes: []models.AttributeDefinition{
+		{Name: "size", Type: models.AttributeTypeText, Validation: `[0-9]+(cm|mm)`},
+		{Name: "code", Type: models.AttributeTypeText, Validation: `^[A-Z]{3}$`},
+		{Name: "weight", Type: models.AttributeTypeNumber, Validation: `[0-9]+`},
+	}}
+
+	tests := []struct {
+		attrs   models.Attributes
+		invalid string
+	}{
+		{models.Attributes{"size": "12cm"}, ""},
+		{models.Attributes{"size": "about 12cm"}, "size"},
+		{models.Attributes{"size": "12cm wide"}, "size"},
+		{models.Attributes{"size": "12mm"}, ""},
+		{models.Attributes{"code": "ABC"}, ""},
+		{models.Attributes{"code": "ABCD"}, "code"},
+		{models.Attributes{"weight": float64(250)}, ""},
+		{models.Attributes{"weight": 2.5}, "weight"},
+	}
+	for _, tt := range tests {
+		errs := ValidateAttributes(schema, tt.attrs, false, "")
+		if tt.invalid == "" && len(errs) > 0 {
+			t.Errorf("ValidateAttributes(%v) = %v, want no errors", tt.attrs, errs)
+		}
+		if tt.invalid != "" && (len(errs) != 1 || errs[tt.invalid] == "") {
+			t.Errorf("ValidateAttributes(%v) = %v, want an error for %s", tt.attrs, errs, tt.invalid)
+		}
+	}
+}
+
+func TestValidateSchemaPattern(t *testing.T) {
+	schema := &models.CategorySchema{Attributes: []models.AttributeDefinition{
+		{Name: "size", Type: models.AttributeTypeText, Validation: `[0-9+`},
+	}}
+	if errs := ValidateSchema(schema); errs["attributes[0].validation"] == "" {
+		t.Errorf("ValidateSchema = %v, want an invalid pattern error", errs)
+	}
+}
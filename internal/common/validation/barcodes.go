@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidBarcode      = errors.New("barcode must be an 8, 12 or 13 digit EAN-8, UPC-A or EAN-13 code")
+	ErrInvalidBarcodeCheck = errors.New("barcode check digit does not match")
+)
+
+// NormalizeBarcode strips whitespace and hyphens from a scanned or typed
+// barcode
+func NormalizeBarcode(value string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(value))
+}
+
+// ValidateBarcode checks the length and GS1 check digit of an EAN-8,
+// UPC-A (12 digits) or EAN-13 barcode
+func ValidateBarcode(barcode string) error {
+	switch len(barcode) {
+	case 8, 12, 13:
+	default:
+		return ErrInvalidBarcode
+	}
+	for _, r := range barcode {
+		if r < '0' || r > '9' {
+			return ErrInvalidBarcode
+		}
+	}
+
+	if barcode[len(barcode)-1]-'0' != GS1CheckDigit(barcode[:len(barcode)-1]) {
+		return ErrInvalidBarcodeCheck
+	}
+	return nil
+}
+
+// GS1CheckDigit computes the check digit for the given digits: weights
+// alternate 3 and 1 starting from the rightmost digit
+func GS1CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			sum += digit * 3
+		} else {
+			sum += digit
+		}
+	}
+	return byte((10 - sum%10) % 10)
+}
+
+// BarcodeVariants returns the forms a barcode may be stored under: a UPC-A
+// code is the same product as the EAN-13 with a leading zero
+func BarcodeVariants(barcode string) []string {
+	switch {
+	case len(barcode) == 12:
+		return []string{barcode, "0" + barcode}
+	case len(barcode) == 13 && barcode[0] == '0':
+		return []string{barcode, barcode[1:]}
+	default:
+		return []string{barcode}
+	}
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGS1CheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"400638133393", 1}, // EAN-13
+		{"590123412345", 7},
+		{"003600029145", 2},
+		{"03600029145", 2}, // UPC-A
+		{"01234567890", 5},
+		{"9638507", 4}, // EAN-8
+		{"7351353", 7},
+		{"000000000000", 0},
+	}
+	for _, tt := range tests {
+		if got := GS1CheckDigit(tt.digits); got != tt.want {
+			t.Errorf("GS1CheckDigit(%q) = %d, want %d", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestValidateBarcode(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    error
+	}{
+		{"4006381333931", nil},
+		{"5901234123457", nil},
+		{"0036000291452", nil},
+		{"036000291452", nil},
+		{"96385074", nil},
+		{"4006381333932", ErrInvalidBarcodeCheck},
+		{"4006381339331", ErrInvalidBarcodeCheck}, // Transposed digits
+		{"036000291453", ErrInvalidBarcodeCheck},
+		{"96385075", ErrInvalidBarcodeCheck},
+		{"9638507", ErrInvalidBarcode},
+		{"40063813339310", ErrInvalidBarcode},
+		{"400638133393X", ErrInvalidBarcode},
+		{"", ErrInvalidBarcode},
+	}
+	for _, tt := range tests {
+		if got := ValidateBarcode(tt.barcode); !errors.Is(got, tt.want) {
+			t.Errorf("ValidateBarcode(%q) = %v, want %v", tt.barcode, got, tt.want)
+		}
+	}
+}
+
+func TestBarcodeVariants(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    []string
+	}{
+		{"036000291452", []string{"036000291452", "0036000291452"}},
+		{"0036000291452", []string{"0036000291452", "036000291452"}},
+		{"4006381333931", []string{"4006381333931"}},
+		{"96385074", []string{"96385074"}},
+	}
+	for _, tt := range tests {
+		if got := BarcodeVariants(tt.barcode); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BarcodeVariants(%q) = %q, want %q", tt.barcode, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeBarcode(t *testing.T) {
+	if got, want := NormalizeBarcode(" 400-6381 333931 "), "4006381333931"; got != want {
+		t.Errorf("NormalizeBarcode = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"fmt"
+
+	"gocom/main/internal/models"
+)
+
+// ValidateScoringRules checks that category scoring rules are consistent:
+// no negative points, partial credit below full credit, ordered length
+// bounds and a publish threshold the maximum score can reach
+func ValidateScoringRules(rules *models.ScoringRules) FieldErrors {
+	errs := FieldErrors{}
+
+	validateLengthRule(errs, "title", rules.Title)
+	validateLengthRule(errs, "description", rules.Description)
+
+	points := map[string]int{
+		"brand_points":         rules.BrandPoints,
+		"sku_points":           rules.SKUPoints,
+		"discount_points":      rules.DiscountPoints,
+		"media_points":         rules.MediaPoints,
+		"media_partial_points": rules.MediaPartialPoints,
+		"attribute_points":     rules.AttributePoints,
+		"min_images":           rules.MinImages,
+	}
+	for field, value := range points {
+		if value < 0 {
+			errs[field] = "must not be negative"
+		}
+	}
+	if rules.MediaPartialPoints > rules.MediaPoints {
+		errs["media_partial_points"] = "must not exceed media_points"
+	}
+
+	maxScore := rules.MaxScore()
+	if maxScore == 0 {
+		errs["points"] = "at least one component must award points"
+	}
+	if rules.MinPublishScore < 0 || rules.MinPublishScore > maxScore {
+		errs["min_publish_score"] = fmt.Sprintf("must be between 0 and the maximum score %d", maxScore)
+	}
+
+	return errs
+}
+
+func validateLengthRule(errs FieldErrors, field string, rule models.LengthRule) {
+	if rule.Points < 0 {
+		errs[field+".points"] = "must not be negative"
+	}
+	if rule.PartialPoints < 0 || rule.PartialPoints > rule.Points {
+		errs[field+".partial_points"] = "must be between 0 and points"
+	}
+	if rule.MinLength < 0 || rule.MinLength > rule.IdealMin {
+		errs[field+".min_length"] = "must be between 0 and ideal_min"
+	}
+	if rule.IdealMax < rule.IdealMin {
+		errs[field+".ideal_max"] = "must not be below ideal_min"
+	}
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPAN        = errors.New("PAN must look like ABCDE1234F")
+	ErrInvalidPANEntity  = errors.New("PAN has an unknown holder type in the 4th character")
+	ErrInvalidGSTIN      = errors.New("GSTIN must be 15 characters: state code, PAN, entity number, Z and check digit")
+	ErrInvalidGSTINState = errors.New("GSTIN has an unknown state code")
+	ErrInvalidGSTINCheck = errors.New("GSTIN check digit does not match")
+	ErrGSTINPANMismatch  = errors.New("GSTIN does not embed the given PAN")
+)
+
+var (
+	panPattern   = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+	gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+)
+
+// 4th character of a PAN identifies the holder type
+const panEntityTypes = "ABCFGHLJPT"
+
+const gstinCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// NormalizeTaxID upper-cases and strips whitespace from a PAN or GSTIN
+func NormalizeTaxID(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// ValidatePAN checks the structure of an Indian Permanent Account Number
+func ValidatePAN(pan string) error {
+	if !panPattern.MatchString(pan) {
+		return ErrInvalidPAN
+	}
+	if !strings.ContainsRune(panEntityTypes, rune(pan[3])) {
+		return ErrInvalidPANEntity
+	}
+	return nil
+}
+
+// ValidateGSTIN checks the structure, state code, embedded PAN and check
+// digit of a GST Identification Number
+func ValidateGSTIN(gstin string) error {
+	if !gstinPattern.MatchString(gstin) {
+		return ErrInvalidGSTIN
+	}
+
+	state, _ := strconv.Atoi(gstin[:2])
+	if !(state >= 1 && state <= 38) && state != 97 && state != 99 {
+		return ErrInvalidGSTINState
+	}
+
+	if err := ValidatePAN(gstin[2:12]); err != nil {
+		return err
+	}
+
+	if GSTINCheckDigit(gstin[:14]) != gstin[14] {
+		return ErrInvalidGSTINCheck
+	}
+	return nil
+}
+
+// ValidateGSTINWithPAN validates both identifiers and that the GSTIN was
+// issued against the PAN
+func ValidateGSTINWithPAN(gstin, pan string) error {
+	if err := ValidatePAN(pan); err != nil {
+		return err
+	}
+	if err := ValidateGSTIN(gstin); err != nil {
+		return err
+	}
+	if gstin[2:12] != pan {
+		return ErrGSTINPANMismatch
+	}
+	return nil
+}
+
+// GSTINCheckDigit computes the mod-36 check character for the first 14
+// characters of a GSTIN
+func GSTINCheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		value := strings.IndexByte(gstinCharset, body[i])
+		factor := 1
+		if i%2 == 1 {
+			factor = 2
+		}
+		product := value * factor
+		sum += product/36 + product%36
+	}
+	return gstinCharset[(36-sum%36)%36]
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePAN(t *testing.T) {
+	tests := []struct {
+		pan  string
+		want error
+	}{
+		{"AAPFU0939F", nil},
+		{"AAACH7409R", nil},
+		{"ABCPE1234F", nil},
+		{"AAPXU0939F", ErrInvalidPANEntity},
+		{"AAAZH7409R", ErrInvalidPANEntity},
+		{"aapfu0939f", ErrInvalidPAN},
+		{"AAPFU0939", ErrInvalidPAN},
+		{"AAPFU09391", ErrInvalidPAN},
+		{"1APFU0939F", ErrInvalidPAN},
+		{"AAPFUO939F", ErrInvalidPAN},
+		{"", ErrInvalidPAN},
+	}
+	for _, tt := range tests {
+		if got := ValidatePAN(tt.pan); !errors.Is(got, tt.want) {
+			t.Errorf("ValidatePAN(%q) = %v, want %v", tt.pan, got, tt.want)
+		}
+	}
+}
+
+func TestGSTINCheckDigit(t *testing.T) {
+	tests := []struct {
+		body string
+		want byte
+	}{
+		{"27AAPFU0939F1Z", 'V'},
+		{"29AAGCB7383J1Z", '4'},
+		{"24AAACC1206D1Z", 'M'},
+		{"33AAACH7409R1Z", '8'},
+	}
+	for _, tt := range tests {
+		if got := GSTINCheckDigit(tt.body); got != tt.want {
+			t.Errorf("GSTINCheckDigit(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestValidateGSTIN(t *testing.T) {
+	tests := []struct {
+		gstin string
+		want  error
+	}{
+		{"27AAPFU0939F1ZV", nil},
+		{"29AAGCB7383J1Z4", nil},
+		{"24AAACC1206D1ZM", nil},
+		{"33AAACH7409R1Z8", nil},
+		{"27AAPFU0939F1ZW", ErrInvalidGSTINCheck},
+		{"27AAPFU0993F1ZV", ErrInvalidGSTINCheck}, // Transposed digits
+		{"28AAPFU0939F1ZV", ErrInvalidGSTINCheck}, // Another state
+		{"00AAPFU0939F1ZV", ErrInvalidGSTINState},
+		{"40AAPFU0939F1ZV", ErrInvalidGSTINState},
+		{"27AAPXU0939F1ZV", ErrInvalidPANEntity},
+		{"27aapfu0939f1zv", ErrInvalidGSTIN},
+		{"27AAPFU0939F1YV", ErrInvalidGSTIN},
+		{"27AAPFU0939F0ZV", ErrInvalidGSTIN},
+		{"27AAPFU0939F1Z", ErrInvalidGSTIN},
+		{"", ErrInvalidGSTIN},
+	}
+	for _, tt := range tests {
+		if got := ValidateGSTIN(tt.gstin); !errors.Is(got, tt.want) {
+			t.Errorf("ValidateGSTIN(%q) = %v, want %v", tt.gstin, got, tt.want)
+		}
+	}
+}
+
+func TestValidateGSTINWithPAN(t *testing.T) {
+	tests := []struct {
+		gstin, pan string
+		want       error
+	}{
+		{"27AAPFU0939F1ZV", "AAPFU0939F", nil},
+		{"27AAPFU0939F1ZV", "AAACH7409R", ErrGSTINPANMismatch},
+		{"27AAPFU0939F1ZW", "AAPFU0939F", ErrInvalidGSTINCheck},
+		{"27AAPFU0939F1ZV", "AAPFU0939", ErrInvalidPAN},
+	}
+	for _, tt := range tests {
+		if got := ValidateGSTINWithPAN(tt.gstin, tt.pan); !errors.Is(got, tt.want) {
+			t.Errorf("ValidateGSTINWithPAN(%q, %q) = %v, want %v", tt.gstin, tt.pan, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTaxID(t *testing.T) {
+	if got, want := NormalizeTaxID(" 27aapfu 0939f1zv\t"), "27AAPFU0939F1ZV"; got != want {
+		t.Errorf("NormalizeTaxID = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,315 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocom/main/internal/common/config"
+)
+
+// LocalRoutePrefix is where LocalStore serves presigned URLs
+const LocalRoutePrefix = "/storage/"
+
+// Content types are kept beside the data under this directory
+const localMetaDir = ".meta"
+
+var (
+	ErrInvalidObjectName = errors.New("invalid bucket or object name")
+	ErrNoSigningKey      = errors.New("local storage needs a signing key")
+)
+
+// LocalStore is the ObjectStore backed by a directory on local disk. It is
+// meant for development and tests; presigned URLs are HMAC-signed links
+// served by the API itself through ServeHTTP.
+type LocalStore struct {
+	Root       string
+	PublicURL  string
+	SigningKey []byte
+}
+
+func ConnectLocal() {
+	cfg := config.AppConfig
+
+	store, err := NewLocalStore(cfg.StorageLocalPath, cfg.StoragePublicURL, cfg.StorageSigningKey)
+	if err != nil {
+		log.Fatal("Failed to initialize local storage:", err)
+	}
+
+	SetStore(store)
+	log.Printf("Local storage initialized at %s", store.Root)
+}
+
+func NewLocalStore(root, publicURL, signingKey string) (*LocalStore, error) {
+	if signingKey == "" {
+		return nil, ErrNoSigningKey
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{
+		Root:       root,
+		PublicURL:  strings.TrimSuffix(publicURL, "/"),
+		SigningKey: []byte(signingKey),
+	}, nil
+}
+
+func (ls *LocalStore) EnsureBucket(bucketName string) error {
+	dir, err := ls.path(bucketName, "")
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0o755)
+}
+
+func (ls *LocalStore) Put(bucketName, objectName string, reader io.Reader, objectSize int64, contentType string) (string, error) {
+	target, err := ls.path(bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write to a temp file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if objectSize >= 0 && written != objectSize {
+		return "", fmt.Errorf("object size mismatch: expected %d bytes, got %d", objectSize, written)
+	}
+
+	if err := ls.writeContentType(bucketName, objectName, contentType); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+
+	log.Printf("Uploaded file: %s/%s (size: %d bytes)", bucketName, objectName, written)
+	return objectPath(bucketName, objectName), nil
+}
+
+func (ls *LocalStore) Get(bucketName, objectName string) (io.ReadCloser, error) {
+	target, err := ls.path(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+func (ls *LocalStore) Stat(bucketName, objectName string) (ObjectInfo, error) {
+	target, err := ls.path(bucketName, objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          objectName,
+		Size:         fi.Size(),
+		ContentType:  ls.readContentType(bucketName, objectName),
+		LastModified: fi.ModTime(),
+	}, nil
+}
+
+// Delete removes an object; like S3, deleting a missing object succeeds
+func (ls *LocalStore) Delete(bucketName, objectName string) error {
+	target, err := ls.path(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	os.Remove(filepath.Join(ls.Root, localMetaDir, bucketName, filepath.FromSlash(objectName)))
+
+	log.Printf("Deleted file: %s/%s", bucketName, objectName)
+	return nil
+}
+
+func (ls *LocalStore) List(bucketName, prefix string) ([]ObjectInfo, error) {
+	dir, err := ls.path(bucketName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := ls.Stat(bucketName, key)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, info)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (ls *LocalStore) PresignGet(bucketName, objectName string, expiry time.Duration) (string, error) {
+	return ls.presign(http.MethodGet, bucketName, objectName, expiry, nil)
+}
+
+// PresignPut signs Content-Type and Content-Length the same way the MinIO
+// backend does, so the upload must send them unchanged
+func (ls *LocalStore) PresignPut(bucketName, objectName string, expiry time.Duration, headers http.Header) (string, error) {
+	return ls.presign(http.MethodPut, bucketName, objectName, expiry, headers)
+}
+
+// ServeHTTP serves GET and PUT requests on presigned URLs
+func (ls *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketName, objectName, err := ParseObjectPath(strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(LocalRoutePrefix, "/")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		http.Error(w, "link has expired", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		expected := ls.signature(http.MethodGet, bucketName, objectName, expires, "", "")
+		if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+			http.Error(w, "signature does not match", http.StatusForbidden)
+			return
+		}
+
+		info, err := ls.Stat(bucketName, objectName)
+		if err != nil {
+			http.Error(w, "object not found", http.StatusNotFound)
+			return
+		}
+		target, _ := ls.path(bucketName, objectName)
+		if info.ContentType != "" {
+			w.Header().Set("Content-Type", info.ContentType)
+		}
+		http.ServeFile(w, r, target)
+
+	case http.MethodPut:
+		contentType := r.Header.Get("Content-Type")
+		contentLength := strconv.FormatInt(r.ContentLength, 10)
+		expected := ls.signature(http.MethodPut, bucketName, objectName, expires, contentType, contentLength)
+		if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+			http.Error(w, "signature does not match", http.StatusForbidden)
+			return
+		}
+
+		if _, err := ls.Put(bucketName, objectName, r.Body, r.ContentLength, contentType); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ls *LocalStore) presign(method, bucketName, objectName string, expiry time.Duration, headers http.Header) (string, error) {
+	if _, err := ls.path(bucketName, objectName); err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(expiry).Unix()
+	signature := ls.signature(method, bucketName, objectName, expires, headers.Get("Content-Type"), headers.Get("Content-Length"))
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signature)
+
+	return ls.PublicURL + strings.TrimSuffix(LocalRoutePrefix, "/") + objectPath(bucketName, objectName) + "?" + query.Encode(), nil
+}
+
+func (ls *LocalStore) signature(method, bucketName, objectName string, expires int64, contentType, contentLength string) string {
+	mac := hmac.New(sha256.New, ls.SigningKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n%s", method, objectPath(bucketName, objectName), expires, contentType, contentLength)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolves an object to a file under Root, rejecting names that would
+// escape the bucket directory
+func (ls *LocalStore) path(bucketName, objectName string) (string, error) {
+	if bucketName == "" || bucketName == localMetaDir || strings.ContainsAny(bucketName, `/\`) || !filepath.IsLocal(bucketName) {
+		return "", ErrInvalidObjectName
+	}
+	if objectName == "" {
+		return filepath.Join(ls.Root, bucketName), nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(objectName)) {
+		return "", ErrInvalidObjectName
+	}
+	return filepath.Join(ls.Root, bucketName, filepath.FromSlash(objectName)), nil
+}
+
+func (ls *LocalStore) writeContentType(bucketName, objectName, contentType string) error {
+	meta := filepath.Join(ls.Root, localMetaDir, bucketName, filepath.FromSlash(objectName))
+	if err := os.MkdirAll(filepath.Dir(meta), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(meta, []byte(contentType), 0o644)
+}
+
+func (ls *LocalStore) readContentType(bucketName, objectName string) string {
+	data, err := os.ReadFile(filepath.Join(ls.Root, localMetaDir, bucketName, filepath.FromSlash(objectName)))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/", "test-signing-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestNewLocalStoreNeedsSigningKey(t *testing.T) {
+	if _, err := NewLocalStore(t.TempDir(), "http://localhost:8080", ""); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("NewLocalStore without a key = %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	store := newTestLocalStore(t)
+	if err := store.EnsureBucket("product-images"); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := store.Put("product-images", "7/photo.jpg", strings.NewReader("jpeg data"), 9, "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/product-images/7/photo.jpg" {
+		t.Errorf("Put path = %q, want /product-images/7/photo.jpg", path)
+	}
+
+	reader, err := store.Get("product-images", "7/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || string(data) != "jpeg data" {
+		t.Errorf("Get = %q, %v; want the stored data", data, err)
+	}
+
+	info, err := store.Stat("product-images", "7/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 9 || info.ContentType != "image/jpeg" || info.Key != "7/photo.jpg" {
+		t.Errorf("Stat = %+v, want 9 bytes of image/jpeg", info)
+	}
+
+	if _, err := store.Put("product-images", "8/photo.jpg", strings.NewReader("png"), 3, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := store.List("product-images", "7/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "7/photo.jpg" {
+		t.Errorf("List(7/) = %+v, want only 7/photo.jpg", objects)
+	}
+
+	if err := store.Delete("product-images", "7/photo.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Stat("product-images", "7/photo.jpg"); err == nil {
+		t.Error("Stat after Delete succeeded")
+	}
+	if err := store.Delete("product-images", "7/photo.jpg"); err != nil {
+		t.Errorf("deleting a missing object = %v, want nil", err)
+	}
+}
+
+func TestLocalStoreRejectsBadNames(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	for _, name := range [][2]string{
+		{"product-images", "../secret"},
+		{"..", "photo.jpg"},
+		{"a/b", "photo.jpg"},
+		{localMetaDir, "photo.jpg"},
+	} {
+		if _, err := store.Put(name[0], name[1], strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidObjectName) {
+			t.Errorf("Put(%q, %q) = %v, want ErrInvalidObjectName", name[0], name[1], err)
+		}
+	}
+	if _, err := store.Put("product-images", "short.jpg", strings.NewReader("x"), 2, ""); err == nil {
+		t.Error("Put with a wrong size succeeded")
+	}
+}
+
+func TestLocalStorePresignGet(t *testing.T) {
+	store := newTestLocalStore(t)
+	if _, err := store.Put("kyc-documents", "3/pan.pdf", strings.NewReader("%PDF"), 4, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := store.PresignGet("kyc-documents", "3/pan.pdf", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link, "http://localhost:8080/storage/kyc-documents/3/pan.pdf?") {
+		t.Fatalf("PresignGet = %q, want a link served under /storage/", link)
+	}
+
+	get := func(link string) *httptest.ResponseRecorder {
+		u, err := url.Parse(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		return rec
+	}
+
+	rec := get(link)
+	if rec.Code != http.StatusOK || rec.Body.String() != "%PDF" || rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("GET signed link = %d %q (%s), want the PDF", rec.Code, rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+
+	u, _ := url.Parse(link)
+	query := u.Query()
+
+	tampered := *u
+	tampered.Path = "/storage/kyc-documents/4/pan.pdf"
+	if rec := get(tampered.String()); rec.Code != http.StatusForbidden {
+		t.Errorf("GET with another object's signature = %d, want 403", rec.Code)
+	}
+
+	later := *u
+	q := url.Values{"expires": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}, "signature": query["signature"]}
+	later.RawQuery = q.Encode()
+	if rec := get(later.String()); rec.Code != http.StatusForbidden {
+		t.Errorf("GET with an extended expiry = %d, want 403", rec.Code)
+	}
+
+	expired, err := store.PresignGet("kyc-documents", "3/pan.pdf", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(expired); rec.Code != http.StatusForbidden {
+		t.Errorf("GET expired link = %d, want 403", rec.Code)
+	}
+
+	other, err := NewLocalStore(store.Root, store.PublicURL, "another-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := other.PresignGet("kyc-documents", "3/pan.pdf", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(foreign); rec.Code != http.StatusForbidden {
+		t.Errorf("GET link signed with another key = %d, want 403", rec.Code)
+	}
+}
+
+func TestLocalStorePresignPut(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "image/png")
+	headers.Set("Content-Length", "4")
+	link, err := store.PresignPut("temp-uploads", "9/upload.png", time.Minute, headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	put := func(body, contentType string) int {
+		req := httptest.NewRequest(http.MethodPut, u.RequestURI(), strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		store.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := put("data", "text/html"); code != http.StatusForbidden {
+		t.Errorf("PUT with another content type = %d, want 403", code)
+	}
+	if code := put("longer data", "image/png"); code != http.StatusForbidden {
+		t.Errorf("PUT with another length = %d, want 403", code)
+	}
+	if code := put("data", "image/png"); code != http.StatusOK {
+		t.Fatalf("PUT with the signed headers = %d, want 200", code)
+	}
+
+	info, err := store.Stat("temp-uploads", "9/upload.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 4 || info.ContentType != "image/png" {
+		t.Errorf("uploaded object = %+v, want 4 bytes of image/png", info)
+	}
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"time"
+
+	"gocom/main/internal/models"
+)
+
+// ResolveMediaLinks fills in the URL and Variants of media from the paths
+// it stores, with links that expire after expiry. Every response carrying
+// media goes through here, so links are built one way.
+func ResolveMediaLinks(store ObjectStore, media *models.Media, expiry time.Duration) error {
+	url, err := ObjectURL(store, media.Path, expiry)
+	if err != nil {
+		return err
+	}
+
+	paths, err := media.GetVariantPaths()
+	if err != nil {
+		return err
+	}
+	variants := make(map[string]string, len(paths))
+	for name, path := range paths {
+		if variants[name], err = ObjectURL(store, path, expiry); err != nil {
+			return err
+		}
+	}
+
+	media.URL = url
+	media.Variants = variants
+	return nil
+}
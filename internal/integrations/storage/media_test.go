@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gocom/main/internal/models"
+)
+
+func TestResolveMediaLinks(t *testing.T) {
+	media := models.Media{Path: "/product-images/products/7/1.jpg"}
+	if err := media.SetVariantPaths(map[string]string{
+		"thumbnail": "/product-images/products/7/1_thumbnail.jpg",
+		"webp":      "https://cdn.example.com/1.webp",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ResolveMediaLinks(NewMemoryStore(), &media, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(media.URL, "memory:///product-images/products/7/1.jpg?") {
+		t.Errorf("URL = %q, want a presigned link", media.URL)
+	}
+	if !strings.HasPrefix(media.Variants["thumbnail"], "memory:///product-images/products/7/1_thumbnail.jpg?") {
+		t.Errorf("thumbnail = %q, want a presigned link", media.Variants["thumbnail"])
+	}
+	if media.Variants["webp"] != "https://cdn.example.com/1.webp" {
+		t.Errorf("webp = %q, want the stored link", media.Variants["webp"])
+	}
+	if media.Path != "/product-images/products/7/1.jpg" {
+		t.Errorf("Path changed to %q", media.Path)
+	}
+}
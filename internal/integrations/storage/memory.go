@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an ObjectStore that keeps objects in memory, for tests.
+// Presigned URLs use a memory:// scheme and cannot be fetched.
+type MemoryStore struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string]memoryObject)}
+}
+
+func (ms *MemoryStore) EnsureBucket(bucketName string) error {
+	return nil
+}
+
+func (ms *MemoryStore) Put(bucketName, objectName string, reader io.Reader, objectSize int64, contentType string) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	if objectSize >= 0 && int64(len(data)) != objectSize {
+		return "", fmt.Errorf("object size mismatch: expected %d bytes, got %d", objectSize, len(data))
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.objects[objectPath(bucketName, objectName)] = memoryObject{
+		data: data,
+		info: ObjectInfo{
+			Key:          objectName,
+			Size:         int64(len(data)),
+			ContentType:  contentType,
+			LastModified: time.Now(),
+		},
+	}
+
+	return objectPath(bucketName, objectName), nil
+}
+
+func (ms *MemoryStore) Get(bucketName, objectName string) (io.ReadCloser, error) {
+	object, err := ms.object(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+func (ms *MemoryStore) Stat(bucketName, objectName string) (ObjectInfo, error) {
+	object, err := ms.object(bucketName, objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return object.info, nil
+}
+
+func (ms *MemoryStore) Delete(bucketName, objectName string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.objects, objectPath(bucketName, objectName))
+	return nil
+}
+
+func (ms *MemoryStore) List(bucketName, prefix string) ([]ObjectInfo, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var objects []ObjectInfo
+	for path, object := range ms.objects {
+		if strings.HasPrefix(path, objectPath(bucketName, prefix)) {
+			objects = append(objects, object.info)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	return objects, nil
+}
+
+func (ms *MemoryStore) PresignGet(bucketName, objectName string, expiry time.Duration) (string, error) {
+	return ms.presign(bucketName, objectName, expiry), nil
+}
+
+func (ms *MemoryStore) PresignPut(bucketName, objectName string, expiry time.Duration, headers http.Header) (string, error) {
+	return ms.presign(bucketName, objectName, expiry), nil
+}
+
+func (ms *MemoryStore) presign(bucketName, objectName string, expiry time.Duration) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	return "memory://" + objectPath(bucketName, objectName) + "?" + query.Encode()
+}
+
+func (ms *MemoryStore) object(bucketName, objectName string) (memoryObject, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	object, ok := ms.objects[objectPath(bucketName, objectName)]
+	if !ok {
+		return memoryObject{}, fmt.Errorf("%s: %w", objectPath(bucketName, objectName), fs.ErrNotExist)
+	}
+	return object, nil
+}
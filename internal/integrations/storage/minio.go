@@ -2,9 +2,9 @@ package storage
 
 import (
     "context"
-    "fmt"
     "io"
     "log"
+    "net/http"
     "time"
     "github.com/minio/minio-go/v7"
     "github.com/minio/minio-go/v7/pkg/credentials"
@@ -13,6 +13,11 @@ import (
 
 var MinIOClient *minio.Client
 
+// MinIOStore is the ObjectStore backed by a MinIO (or any S3) server
+type MinIOStore struct {
+    Client *minio.Client
+}
+
 func ConnectMinIO() {
     cfg := config.AppConfig
     
@@ -26,20 +31,21 @@ func ConnectMinIO() {
         log.Fatal("Failed to initialize MinIO client:", err)
     }
     
+    SetStore(&MinIOStore{Client: MinIOClient})
     log.Println("MinIO client initialized successfully")
 }
 
 
-func CreateBucketIfNotExists(bucketName string) error {
+func (ms *MinIOStore) EnsureBucket(bucketName string) error {
     ctx := context.Background()
     
-    exists, err := MinIOClient.BucketExists(ctx, bucketName)
+    exists, err := ms.Client.BucketExists(ctx, bucketName)
     if err != nil {
         return err
     }
     
     if !exists {
-        err = MinIOClient.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{})
+        err = ms.Client.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{})
         if err != nil {
             return err
         }
@@ -51,10 +57,10 @@ func CreateBucketIfNotExists(bucketName string) error {
     return nil
 }
 
-func UploadFile(bucketName, objectName string, reader io.Reader, objectSize int64, contentType string) (string, error) {
+func (ms *MinIOStore) Put(bucketName, objectName string, reader io.Reader, objectSize int64, contentType string) (string, error) {
     ctx := context.Background()
     
-    info, err := MinIOClient.PutObject(ctx, bucketName, objectName, reader, objectSize, minio.PutObjectOptions{
+    info, err := ms.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, minio.PutObjectOptions{
         ContentType: contentType,
     })
     
@@ -63,13 +69,26 @@ func UploadFile(bucketName, objectName string, reader io.Reader, objectSize int6
     }
     
     log.Printf("Uploaded file: %s/%s (size: %d bytes)", bucketName, objectName, info.Size)
-    return fmt.Sprintf("/%s/%s", bucketName, objectName), nil
+    return objectPath(bucketName, objectName), nil
+}
+
+func (ms *MinIOStore) PresignGet(bucketName, objectName string, expiry time.Duration) (string, error) {
+    ctx := context.Background()
+    
+    presignedURL, err := ms.Client.PresignedGetObject(ctx, bucketName, objectName, expiry, nil)
+    if err != nil {
+        return "", err
+    }
+    
+    return presignedURL.String(), nil
 }
 
-func GetPresignedURL(bucketName, objectName string, expiry time.Duration) (string, error) {
+// PresignPut returns a URL the client can PUT an object to directly.
+// Headers passed here are signed, so the upload must send them unchanged.
+func (ms *MinIOStore) PresignPut(bucketName, objectName string, expiry time.Duration, headers http.Header) (string, error) {
     ctx := context.Background()
     
-    presignedURL, err := MinIOClient.PresignedGetObject(ctx, bucketName, objectName, expiry, nil)
+    presignedURL, err := ms.Client.PresignHeader(ctx, http.MethodPut, bucketName, objectName, expiry, nil, headers)
     if err != nil {
         return "", err
     }
@@ -77,11 +96,22 @@ func GetPresignedURL(bucketName, objectName string, expiry time.Duration) (strin
     return presignedURL.String(), nil
 }
 
+// Stat returns object metadata without downloading it
+func (ms *MinIOStore) Stat(bucketName, objectName string) (ObjectInfo, error) {
+    ctx := context.Background()
+    
+    info, err := ms.Client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+    if err != nil {
+        return ObjectInfo{}, err
+    }
+    
+    return toObjectInfo(info), nil
+}
 
-func DownloadFile(bucketName, objectName string) (*minio.Object, error) {
+func (ms *MinIOStore) Get(bucketName, objectName string) (io.ReadCloser, error) {
     ctx := context.Background()
     
-    object, err := MinIOClient.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+    object, err := ms.Client.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
     if err != nil {
         return nil, err
     }
@@ -89,10 +119,10 @@ func DownloadFile(bucketName, objectName string) (*minio.Object, error) {
     return object, nil
 }
 
-func DeleteFile(bucketName, objectName string) error {
+func (ms *MinIOStore) Delete(bucketName, objectName string) error {
     ctx := context.Background()
     
-    err := MinIOClient.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
+    err := ms.Client.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
     if err != nil {
         return err
     }
@@ -101,40 +131,32 @@ func DeleteFile(bucketName, objectName string) error {
     return nil
 }
 
-// ListFiles lists all files in a bucket with prefix
-func ListFiles(bucketName, prefix string) ([]minio.ObjectInfo, error) {
+// List lists all files in a bucket with prefix
+func (ms *MinIOStore) List(bucketName, prefix string) ([]ObjectInfo, error) {
     ctx := context.Background()
     
-    var objects []minio.ObjectInfo
+    var objects []ObjectInfo
     
-    for object := range MinIOClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+    for object := range ms.Client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
         Prefix:    prefix,
         Recursive: true,
     }) {
         if object.Err != nil {
             return nil, object.Err
         }
-        objects = append(objects, object)
+        objects = append(objects, toObjectInfo(object))
     }
     
     return objects, nil
 }
 
-func InitializeBuckets() error {
-    buckets := []string{
-        "kyc-documents",    // KYC verification files
-        "product-images",   // Product photos
-        "seller-documents", // Business documents
-        "temp-uploads",     // Temporary file storage
+func toObjectInfo(info minio.ObjectInfo) ObjectInfo {
+    return ObjectInfo{
+        Key:          info.Key,
+        Size:         info.Size,
+        ContentType:  info.ContentType,
+        LastModified: info.LastModified,
     }
-    
-    for _, bucket := range buckets {
-        if err := CreateBucketIfNotExists(bucket); err != nil {
-            return fmt.Errorf("failed to create bucket %s: %v", bucket, err)
-        }
-    }
-    
-    return nil
 }
 
 // GetMinIOClient returns the MinIO 
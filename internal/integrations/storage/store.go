@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gocom/main/internal/common/config"
+)
+
+// Supported storage backends
+const (
+	BackendMinIO = "minio"
+	BackendLocal = "local"
+)
+
+// ObjectInfo describes a stored object independently of the backend
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// ObjectStore is implemented by every storage backend. Services take an
+// ObjectStore so they can run against MinIO, local disk or a test fake.
+type ObjectStore interface {
+	EnsureBucket(bucket string) error
+	Put(bucket, object string, reader io.Reader, size int64, contentType string) (string, error)
+	Get(bucket, object string) (io.ReadCloser, error)
+	Stat(bucket, object string) (ObjectInfo, error)
+	Delete(bucket, object string) error
+	List(bucket, prefix string) ([]ObjectInfo, error)
+	PresignGet(bucket, object string, expiry time.Duration) (string, error)
+	PresignPut(bucket, object string, expiry time.Duration, headers http.Header) (string, error)
+}
+
+var defaultStore ObjectStore
+
+// Connect initialises the backend selected by STORAGE_BACKEND
+func Connect() {
+	switch config.AppConfig.StorageBackend {
+	case BackendMinIO:
+		ConnectMinIO()
+	case BackendLocal:
+		ConnectLocal()
+	default:
+		log.Fatalf("Unknown storage backend: %s", config.AppConfig.StorageBackend)
+	}
+}
+
+// SetStore replaces the default store, e.g. with a fake in tests
+func SetStore(store ObjectStore) {
+	defaultStore = store
+}
+
+// GetStore returns the default store used by services
+func GetStore() ObjectStore {
+	return defaultStore
+}
+
+// ParseObjectPath splits a "/bucket/object" path returned by Put
+func ParseObjectPath(path string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid object path: %s", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ObjectURL turns a "/bucket/object" path returned by Put into a link that
+// expires after expiry. Anything else is taken to be a link already and
+// returned as it is.
+func ObjectURL(store ObjectStore, path string, expiry time.Duration) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return path, nil
+	}
+	bucket, object, err := ParseObjectPath(path)
+	if err != nil {
+		return "", err
+	}
+	return store.PresignGet(bucket, object, expiry)
+}
+
+func objectPath(bucket, object string) string {
+	return fmt.Sprintf("/%s/%s", bucket, object)
+}
+
+func InitializeBuckets() error {
+	buckets := []string{
+		"catalog-exports",  // Generated catalog exports
+		"catalog-imports",  // Bulk import files and error reports
+		"kyc-documents",    // KYC verification files
+		"product-images",   // Product photos
+		"seller-documents", // Business documents
+		"temp-uploads",     // Temporary file storage
+	}
+
+	for _, bucket := range buckets {
+		if err := defaultStore.EnsureBucket(bucket); err != nil {
+			return fmt.Errorf("failed to create bucket %s: %v", bucket, err)
+		}
+	}
+
+	return nil
+}
+
+// Package-level helpers operating on the default store
+
+func CreateBucketIfNotExists(bucketName string) error {
+	return defaultStore.EnsureBucket(bucketName)
+}
+
+func UploadFile(bucketName, objectName string, reader io.Reader, objectSize int64, contentType string) (string, error) {
+	return defaultStore.Put(bucketName, objectName, reader, objectSize, contentType)
+}
+
+func GetPresignedURL(bucketName, objectName string, expiry time.Duration) (string, error) {
+	return defaultStore.PresignGet(bucketName, objectName, expiry)
+}
+
+func GetPresignedPutURL(bucketName, objectName string, expiry time.Duration, headers http.Header) (string, error) {
+	return defaultStore.PresignPut(bucketName, objectName, expiry, headers)
+}
+
+func StatFile(bucketName, objectName string) (ObjectInfo, error) {
+	return defaultStore.Stat(bucketName, objectName)
+}
+
+func DownloadFile(bucketName, objectName string) (io.ReadCloser, error) {
+	return defaultStore.Get(bucketName, objectName)
+}
+
+func DeleteFile(bucketName, objectName string) error {
+	return defaultStore.Delete(bucketName, objectName)
+}
+
+func ListFiles(bucketName, prefix string) ([]ObjectInfo, error) {
+	return defaultStore.List(bucketName, prefix)
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseObjectPath(t *testing.T) {
+	tests := []struct {
+		path           string
+		bucket, object string
+		ok             bool
+	}{
+		{"/product-images/7/photo.jpg", "product-images", "7/photo.jpg", true},
+		{"product-images/photo.jpg", "product-images", "photo.jpg", true},
+		{"/product-images/", "", "", false},
+		{"/product-images", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		bucket, object, err := ParseObjectPath(tt.path)
+		if (err == nil) != tt.ok || bucket != tt.bucket || object != tt.object {
+			t.Errorf("ParseObjectPath(%q) = %q, %q, %v", tt.path, bucket, object, err)
+		}
+	}
+}
+
+func TestMemoryStoreAsDefault(t *testing.T) {
+	previous := GetStore()
+	t.Cleanup(func() { SetStore(previous) })
+
+	store := NewMemoryStore()
+	SetStore(store)
+
+	path, err := UploadFile("catalog-exports", "5/export.csv", strings.NewReader("a,b"), 3, "text/csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket, object, err := ParseObjectPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := DownloadFile(bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	if string(data) != "a,b" {
+		t.Errorf("DownloadFile = %q, want a,b", data)
+	}
+
+	files, err := ListFiles("catalog-exports", "5/")
+	if err != nil || len(files) != 1 || files[0].ContentType != "text/csv" {
+		t.Errorf("ListFiles = %+v, %v; want the export", files, err)
+	}
+
+	link, err := GetPresignedURL(bucket, object, 0)
+	if err != nil || !strings.HasPrefix(link, "memory:///catalog-exports/5/export.csv?") {
+		t.Errorf("GetPresignedURL = %q, %v", link, err)
+	}
+
+	if err := DeleteFile(bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StatFile(bucket, object); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("StatFile after delete = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestObjectURL(t *testing.T) {
+	store := NewMemoryStore()
+
+	tests := []struct {
+		path   string
+		prefix string
+		ok     bool
+	}{
+		{"/product-images/7/photo.jpg", "memory:///product-images/7/photo.jpg?expires=", true},
+		{"https://cdn.example.com/photo.jpg", "https://cdn.example.com/photo.jpg", true},
+		{"/product-images", "", false},
+	}
+	for _, tt := range tests {
+		link, err := ObjectURL(store, tt.path, time.Hour)
+		if (err == nil) != tt.ok || !strings.HasPrefix(link, tt.prefix) {
+			t.Errorf("ObjectURL(%q) = %q, %v", tt.path, link, err)
+		}
+	}
+}
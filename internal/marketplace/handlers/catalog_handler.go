@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/marketplace/services"
+)
+
+type CatalogHandler struct {
+	CatalogService *services.CatalogService
+}
+
+func NewCatalogHandler() *CatalogHandler {
+	return &CatalogHandler{
+		CatalogService: services.NewCatalogService(),
+	}
+}
+
+// List categories
+// GET /v1/categories
+func (ch *CatalogHandler) ListCategories(c *gin.Context) {
+	categories, err := ch.CatalogService.ListCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+	})
+}
+
+// Get the category tree
+// GET /v1/categories/tree
+func (ch *CatalogHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := ch.CatalogService.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tree,
+	})
+}
+
+// List products in a category
+// GET /v1/categories/:id/products
+func (ch *CatalogHandler) ListCategoryProducts(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var filters services.CatalogFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := uint(categoryID)
+	filters.CategoryID = &id
+
+	ch.listProducts(c, filters)
+}
+
+// List products
+// GET /v1/products
+func (ch *CatalogHandler) ListProducts(c *gin.Context) {
+	var filters services.CatalogFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ch.listProducts(c, filters)
+}
+
+// Get product detail
+// GET /v1/products/:id
+func (ch *CatalogHandler) GetProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	product, err := ch.CatalogService.GetProduct(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+	})
+}
+
+// Search products with facets
+// GET /v1/search
+func (ch *CatalogHandler) Search(c *gin.Context) {
+	var req services.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for param, target := range map[string]**decimal.Decimal{
+		"price_min": &req.PriceMin,
+		"price_max": &req.PriceMax,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		price, err := decimal.NewFromString(value)
+		if err != nil || price.IsNegative() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a non-negative number"})
+			return
+		}
+		*target = &price
+	}
+	req.Attributes = services.ParseAttributeFilters(c.Request.URL.Query())
+	req.Client = c.ClientIP()
+
+	result, err := ch.CatalogService.Search(&req)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, services.ErrSearchSort),
+			stderrors.Is(err, services.ErrSearchPriceBucket):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case stderrors.Is(err, services.ErrSearchCategory):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// Suggest completions while the buyer types
+// GET /v1/search/suggest
+func (ch *CatalogHandler) Suggest(c *gin.Context) {
+	var req services.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, err := ch.CatalogService.Suggest(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    suggestions,
+	})
+}
+
+func (ch *CatalogHandler) listProducts(c *gin.Context, filters services.CatalogFilters) {
+	filters.Client = c.ClientIP()
+	products, total, err := ch.CatalogService.ListProducts(filters)
+	if err != nil {
+		if stderrors.Is(err, services.ErrSearchCategory) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"products": products,
+			"total":    total,
+			"page":     filters.Page,
+			"limit":    filters.Limit,
+		},
+	})
+}
@@ -1 +1,26 @@
-package marketplace
\ No newline at end of file
+package marketplace
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/marketplace/handlers"
+)
+
+func SetupRoutes(r *gin.Engine) {
+	// Initialize handlers
+	catalogHandler := handlers.NewCatalogHandler()
+
+	// API v1 group, catalog browsing is public
+	v1 := r.Group("/v1")
+
+	// Catalog routes
+	{
+		v1.GET("/categories", catalogHandler.ListCategories)
+		v1.GET("/categories/tree", catalogHandler.GetCategoryTree)
+		v1.GET("/categories/:id/products", catalogHandler.ListCategoryProducts)
+		v1.GET("/products", catalogHandler.ListProducts)
+		v1.GET("/products/:id", catalogHandler.GetProduct)
+		v1.GET("/search", catalogHandler.Search)
+		v1.GET("/search/suggest", catalogHandler.Suggest)
+	}
+}
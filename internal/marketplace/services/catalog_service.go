@@ -0,0 +1,411 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+)
+
+// Most hits taken from the search index for one search. The index applies
+// category and brand filters first; facets and stock only see these hits.
+const maxIndexHits = 1000
+
+type CatalogService struct {
+	DB *gorm.DB
+
+	// Search index for text searches, nil to search MySQL directly
+	Index search.Index
+
+	// Product media is presigned from here
+	Store storage.ObjectStore
+
+	// Category tree cache, categories are edited by admin-api so changes
+	// show up here once the cached tree expires
+	treeMu       sync.Mutex
+	tree         []CategoryTreeNode
+	treeLoadedAt time.Time
+}
+
+func NewCatalogService() *CatalogService {
+	return &CatalogService{
+		DB:    db.GetDB(),
+		Index: search.GetIndex(),
+		Store: storage.GetStore(),
+	}
+}
+
+// List active categories, flat, leaving out categories under an inactive
+// ancestor
+func (cs *CatalogService) ListCategories() ([]CategoryResponse, error) {
+	tree, err := cs.GetCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CategoryResponse, 0)
+	var walk func(nodes []CategoryTreeNode, parentID *uint)
+	walk = func(nodes []CategoryTreeNode, parentID *uint) {
+		for _, node := range nodes {
+			result = append(result, CategoryResponse{
+				ID:       node.ID,
+				ParentID: parentID,
+				Name:     node.Name,
+				Slug:     node.Slug,
+			})
+			id := node.ID
+			walk(node.Children, &id)
+		}
+	}
+	walk(tree, nil)
+
+	return result, nil
+}
+
+// Get the active category tree, served from cache while it is fresh
+func (cs *CatalogService) GetCategoryTree() ([]CategoryTreeNode, error) {
+	cs.treeMu.Lock()
+	defer cs.treeMu.Unlock()
+
+	if cs.tree != nil && time.Since(cs.treeLoadedAt) < config.AppConfig.CategoryTreeCacheTTL {
+		return cs.tree, nil
+	}
+
+	var categories []models.Category
+	if err := cs.DB.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	cs.tree = newCategoryTree(models.BuildCategoryTree(categories, false))
+	cs.treeLoadedAt = time.Now()
+	return cs.tree, nil
+}
+
+// List published products from approved sellers
+func (cs *CatalogService) ListProducts(filters CatalogFilters) ([]ProductResponse, int64, error) {
+	var products []models.Product
+	var total int64
+
+	query := cs.visibleProducts()
+
+	// Apply filters, a category covers its whole subtree
+	var categoryIDs []uint
+	if filters.CategoryID != nil {
+		tree, err := cs.GetCategoryTree()
+		if err != nil {
+			return nil, 0, err
+		}
+		categoryIDs, _, _, err = categoryScope(tree, filters.CategoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("products.category_id IN ?", categoryIDs)
+	}
+	if filters.Brand != "" {
+		query = query.Where("products.brand = ?", filters.Brand)
+	}
+	productSearch := search.NewProductSearch(filters.Query)
+	if productSearch != nil && cs.Index != nil {
+		return cs.listIndexedProducts(query, filters, categoryIDs)
+	}
+	if productSearch != nil {
+		productSearch.ActiveSKUsOnly = true
+		query = productSearch.Filter(query)
+	}
+
+	// Get total count
+	query.Count(&total)
+	if productSearch != nil && isUnfilteredSearch(filters) {
+		cs.logQuery(filters.Query, total, filters.Client)
+	}
+
+	// Best matches first when searching, newest first otherwise
+	if productSearch != nil {
+		query = productSearch.OrderByRelevance(query)
+	}
+
+	// Apply pagination
+	offset := (filters.Page - 1) * filters.Limit
+	err := preloadListing(query).
+		Offset(offset).
+		Limit(filters.Limit).
+		Order("products.created_at DESC").
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]ProductResponse, 0, len(products))
+	for _, product := range products {
+		response, err := cs.newProductResponse(&product)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, response)
+	}
+	return result, total, nil
+}
+
+// List the products of a filtered query that the search index matches,
+// best matches first. The ranking comes from the index, so paging happens
+// on the matching IDs before the page is loaded. Hits past the index limit
+// are not checked against the database, so the total counts them as if
+// they were visible.
+func (cs *CatalogService) listIndexedProducts(query *gorm.DB, filters CatalogFilters, categoryIDs []uint) ([]ProductResponse, int64, error) {
+	ids, scores, indexTotal, err := cs.indexHits(search.Query{
+		Text:        filters.Query,
+		CategoryIDs: categoryIDs,
+		Brand:       filters.Brand,
+		// Leave room for hits the database check drops
+		Limit: max(maxIndexHits, 2*filters.Page*filters.Limit),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matched []uint
+	if len(ids) > 0 {
+		if err := query.Where("products.id IN ?", ids).Pluck("products.id", &matched).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	total := int64(len(matched) + indexTotal - len(ids))
+	if isUnfilteredSearch(filters) {
+		cs.logQuery(filters.Query, total, filters.Client)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if scores[matched[i]] != scores[matched[j]] {
+			return scores[matched[i]] > scores[matched[j]]
+		}
+		return matched[i] > matched[j]
+	})
+
+	result := make([]ProductResponse, 0)
+	start := min((filters.Page-1)*filters.Limit, len(matched))
+	page := matched[start:min(start+filters.Limit, len(matched))]
+	if len(page) == 0 {
+		return result, total, nil
+	}
+
+	products, err := cs.loadListing(page)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, product := range products {
+		response, err := cs.newProductResponse(product)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, response)
+	}
+	return result, total, nil
+}
+
+// Only the first page of a search across the whole catalog is logged, so
+// paging and narrowed searches do not skew query stats
+func isUnfilteredSearch(filters CatalogFilters) bool {
+	return filters.Page == 1 && filters.CategoryID == nil && filters.Brand == ""
+}
+
+// Rank the products matching a query with the search index, returning
+// their IDs best first, their scores and how many matched in all
+func (cs *CatalogService) indexHits(query search.Query) ([]uint, map[uint]float64, int, error) {
+	hits, total, err := cs.Index.Search(query)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	ids := make([]uint, len(hits))
+	scores := make(map[uint]float64, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ProductID
+		scores[hit.ProductID] = hit.Score
+	}
+	return ids, scores, total, nil
+}
+
+// Load products for a listing page, in the order of ids
+func (cs *CatalogService) loadListing(ids []uint) ([]*models.Product, error) {
+	var products []models.Product
+	if err := preloadListing(cs.DB.Where("id IN ?", ids)).Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	ordered := make([]*models.Product, 0, len(products))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			ordered = append(ordered, product)
+		}
+	}
+	return ordered, nil
+}
+
+// Relations shown in product listings
+func preloadListing(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Category").
+		Preload("SKUs", "is_active = ?", true).
+		Preload("Media", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort ASC")
+		})
+}
+
+// Get a published product with its SKUs and media
+func (cs *CatalogService) GetProduct(productID uint) (*ProductResponse, error) {
+	var product models.Product
+
+	err := cs.visibleProducts().
+		Preload("Category").
+		Preload("Seller").
+		Preload("SKUs", "is_active = ?", true).
+		Preload("Media", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort ASC")
+		}).
+		Where("products.id = ?", productID).
+		First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := cs.newProductResponse(&product)
+	if err != nil {
+		return nil, err
+	}
+	response.SellerName = product.Seller.DisplayName
+	return &response, nil
+}
+
+// Products buyers are allowed to see
+func (cs *CatalogService) visibleProducts() *gorm.DB {
+	return cs.DB.Model(&models.Product{}).
+		Joins("JOIN sellers ON sellers.id = products.seller_id").
+		Where("products.status = ? AND sellers.status = ?",
+			models.ProductStatusPublished, models.SellerStatusApproved)
+}
+
+func newCategoryResponse(category *models.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:       category.ID,
+		ParentID: category.ParentID,
+		Name:     category.Name,
+		Slug:     category.SEOSlug,
+	}
+}
+
+func newCategoryTree(categories []models.Category) []CategoryTreeNode {
+	nodes := make([]CategoryTreeNode, 0, len(categories))
+	for _, category := range categories {
+		nodes = append(nodes, CategoryTreeNode{
+			ID:       category.ID,
+			Name:     category.Name,
+			Slug:     category.SEOSlug,
+			Children: newCategoryTree(category.Children),
+		})
+	}
+	return nodes
+}
+
+// Build the buyer view of a product, with presigned media links
+func (cs *CatalogService) newProductResponse(product *models.Product) (ProductResponse, error) {
+	response := ProductResponse{
+		ID:          product.ID,
+		SellerID:    product.SellerID,
+		Title:       product.Title,
+		Description: product.Description,
+		Brand:       product.Brand,
+		Attributes:  product.Attributes,
+		Category:    newCategoryResponse(&product.Category),
+		SKUs:        make([]SKUResponse, 0, len(product.SKUs)),
+		Media:       make([]MediaResponse, 0, len(product.Media)),
+	}
+
+	for _, sku := range product.SKUs {
+		response.SKUs = append(response.SKUs, SKUResponse{
+			ID:         sku.ID,
+			SKUCode:    sku.SKUCode,
+			Attributes: sku.Attributes,
+			PriceMRP:   sku.PriceMRP,
+			PriceSell:  sku.PriceSell,
+		})
+	}
+	for _, media := range product.Media {
+		if err := storage.ResolveMediaLinks(cs.Store, &media, config.AppConfig.MediaURLExpiry); err != nil {
+			return response, err
+		}
+		response.Media = append(response.Media, MediaResponse{
+			URL:      media.URL,
+			Type:     media.Type,
+			AltText:  media.AltText,
+			Variants: media.Variants,
+		})
+	}
+
+	return response, nil
+}
+
+// Request DTOs
+type CatalogFilters struct {
+	CategoryID *uint  `form:"category_id"`
+	Brand      string `form:"brand"`
+	Query      string `form:"q" binding:"max=200"`
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	Limit      int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Client     string `form:"-"` // Client IP, for search logging
+}
+
+// Response DTOs, these deliberately leave out seller-only fields such as
+// Score, Status and Barcode
+type CategoryResponse struct {
+	ID       uint   `json:"id"`
+	ParentID *uint  `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+type CategoryTreeNode struct {
+	ID       uint               `json:"id"`
+	Name     string             `json:"name"`
+	Slug     string             `json:"slug"`
+	Children []CategoryTreeNode `json:"children"`
+}
+
+type ProductResponse struct {
+	ID          uint             `json:"id"`
+	SellerID    uint             `json:"seller_id"`
+	SellerName  string           `json:"seller_name,omitempty"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Brand       string           `json:"brand"`
+	Attributes  json.RawMessage  `json:"attributes"`
+	Category    CategoryResponse `json:"category"`
+	SKUs        []SKUResponse    `json:"skus"`
+	Media       []MediaResponse  `json:"media"`
+}
+
+type SKUResponse struct {
+	ID         uint            `json:"id"`
+	SKUCode    string          `json:"sku_code"`
+	Attributes json.RawMessage `json:"attributes"`
+	PriceMRP   decimal.Decimal `json:"price_mrp"`
+	PriceSell  decimal.Decimal `json:"price_sell"`
+}
+
+type MediaResponse struct {
+	URL      string            `json:"url"`
+	Type     string            `json:"type"`
+	AltText  string            `json:"alt_text"`
+	Variants map[string]string `json:"variants,omitempty"`
+}
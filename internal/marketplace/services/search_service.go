@@ -0,0 +1,697 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+)
+
+// Sort orders of a marketplace search
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortNewest    = "newest"
+	SearchSortPriceAsc  = "price_asc"
+	SearchSortPriceDesc = "price_desc"
+	SearchSortRating    = "rating"
+)
+
+// Query parameters "attr.<name>" filter on SKU attributes
+const searchAttributePrefix = "attr."
+
+var (
+	ErrSearchSort        = errors.New("sort must be one of: relevance, newest, price_asc, price_desc, rating")
+	ErrSearchPriceBucket = errors.New("unknown price bucket")
+	ErrSearchCategory    = errors.New("category not found")
+)
+
+// Most SKU rows a search loads for filtering, facets and sorting. Rows are
+// loaded in the requested order so a search with more matches loses its
+// tail, and the result says it was truncated.
+const maxSearchCandidates = 5000
+
+// priceBuckets are the lower bounds of the price facet buckets; the last
+// bucket is open-ended
+var priceBuckets = []int64{0, 500, 1000, 2500, 5000, 10000}
+
+// searchCandidate is an active, in-stock SKU of a visible product matching
+// the text and category of a search, before facet filters are applied
+type searchCandidate struct {
+	SKUID      uint
+	ProductID  uint
+	PriceSell  decimal.Decimal
+	Attributes json.RawMessage
+	Brand      string
+	CategoryID uint
+	CreatedAt  time.Time
+	Relevance  float64
+
+	attrs  map[string]string
+	bucket string
+}
+
+// Search published, in-stock products with facet counts. Brand, price and
+// attribute filters are multi-select: values of one filter are ORed, and
+// each facet is counted with every filter except its own applied, so
+// buyers can see what selecting another value would add. Price and
+// attribute filters must be met by the same SKU.
+func (cs *CatalogService) Search(req *SearchRequest) (*SearchResult, error) {
+	sortBy := req.Sort
+	if sortBy == "" {
+		sortBy = SearchSortNewest
+		if strings.TrimSpace(req.Query) != "" {
+			sortBy = SearchSortRelevance
+		}
+	}
+	switch sortBy {
+	case SearchSortRelevance, SearchSortNewest, SearchSortPriceAsc, SearchSortPriceDesc, SearchSortRating:
+	default:
+		return nil, ErrSearchSort
+	}
+	for _, bucket := range req.Prices {
+		if !isPriceBucket(bucket) {
+			return nil, ErrSearchPriceBucket
+		}
+	}
+
+	// Category filter covers the whole subtree; the facet drills one level
+	// further down
+	tree, err := cs.GetCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+	subtree, facetNodes, facetOf, err := categoryScope(tree, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, truncated, err := cs.searchCandidates(req.Query, subtree, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := newSearchFilter(req)
+	result := &SearchResult{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Truncated: truncated,
+		Facets: SearchFacets{
+			Categories: make([]CategoryFacet, 0),
+			Brands:     make([]FacetValue, 0),
+			Prices:     make([]FacetValue, 0),
+			Attributes: make([]AttributeFacet, 0),
+		},
+	}
+
+	// Per product: the cheapest SKU passing every filter, and for each
+	// facet value the products that would match if it were selected
+	matched := make(map[uint]*searchCandidate)
+	brandCounts := newFoldedFacetCounter()
+	priceCounts := newFacetCounter()
+	categoryCounts := newFacetCounter()
+	attrCounts := make(map[string]*facetCounter)
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		failed := filter.failures(candidate)
+
+		if len(failed) == 0 {
+			if best, ok := matched[candidate.ProductID]; !ok || candidate.PriceSell.LessThan(best.PriceSell) {
+				matched[candidate.ProductID] = candidate
+			}
+			if facet, ok := facetOf[candidate.CategoryID]; ok {
+				categoryCounts.add(strconv.FormatUint(uint64(facet), 10), candidate.ProductID)
+			}
+		}
+		if len(failed) == 0 || (len(failed) == 1 && failed[0] == facetBrand) {
+			brandCounts.add(candidate.Brand, candidate.ProductID)
+		}
+		if len(failed) == 0 || (len(failed) == 1 && failed[0] == facetPrice) {
+			priceCounts.add(candidate.bucket, candidate.ProductID)
+		}
+		for name, value := range candidate.attrs {
+			if len(failed) == 0 || (len(failed) == 1 && failed[0] == searchAttributePrefix+name) {
+				if attrCounts[name] == nil {
+					attrCounts[name] = newFacetCounter()
+				}
+				attrCounts[name].add(value, candidate.ProductID)
+			}
+		}
+	}
+
+	for _, node := range facetNodes {
+		if count := categoryCounts.count(strconv.FormatUint(uint64(node.ID), 10)); count > 0 {
+			result.Facets.Categories = append(result.Facets.Categories, CategoryFacet{
+				ID: node.ID, Name: node.Name, Slug: node.Slug, Count: count,
+			})
+		}
+	}
+	result.Facets.Brands = brandCounts.values(filter.brands, false)
+	result.Facets.Prices = priceCounts.values(filter.prices, true)
+	for _, name := range sortedKeys(attrCounts) {
+		result.Facets.Attributes = append(result.Facets.Attributes, AttributeFacet{
+			Name:   name,
+			Values: attrCounts[name].values(filter.attrs[name], false),
+		})
+	}
+
+	// Sort and page the matching products
+	hits := make([]*searchCandidate, 0, len(matched))
+	for _, candidate := range matched {
+		hits = append(hits, candidate)
+	}
+	result.Total = int64(len(hits))
+
+	// Log what the text found across the catalog, before facet filters
+	// narrowed it down
+	if req.Page == 1 && req.CategoryID == nil {
+		found := make(map[uint]bool)
+		for i := range candidates {
+			found[candidates[i].ProductID] = true
+		}
+		cs.logQuery(req.Query, int64(len(found)), req.Client)
+	}
+
+	var ratings map[uint]productRating
+	if len(hits) > 0 {
+		ids := make([]uint, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.ProductID
+		}
+		if ratings, err = cs.productRatings(ids); err != nil {
+			return nil, err
+		}
+	}
+	sortSearchHits(hits, sortBy, ratings)
+
+	start := (req.Page - 1) * req.Limit
+	if start > len(hits) {
+		start = len(hits)
+	}
+	end := start + req.Limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+	page := hits[start:end]
+
+	result.Products = make([]SearchHit, 0, len(page))
+	if len(page) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, len(page))
+	for i, hit := range page {
+		ids[i] = hit.ProductID
+	}
+	products, err := cs.loadListing(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	for _, hit := range page {
+		product, ok := byID[hit.ProductID]
+		if !ok {
+			continue
+		}
+		response, err := cs.newProductResponse(product)
+		if err != nil {
+			return nil, err
+		}
+		rating := ratings[hit.ProductID]
+		result.Products = append(result.Products, SearchHit{
+			ProductResponse: response,
+			PriceFrom:       hit.PriceSell,
+			Rating:          rating.Average,
+			ReviewCount:     rating.Count,
+		})
+	}
+
+	return result, nil
+}
+
+// Load the SKU rows a search can return: active and in stock, of visible
+// products in the category subtree that match the search text. At most
+// maxSearchCandidates rows of maxIndexHits products are loaded, best first
+// by sortBy; the flag tells whether there were more.
+func (cs *CatalogService) searchCandidates(text string, categoryIDs []uint, sortBy string) ([]searchCandidate, bool, error) {
+	query := cs.DB.Table("skus").
+		Joins("JOIN products ON products.id = skus.product_id").
+		Joins("JOIN sellers ON sellers.id = products.seller_id").
+		Where("products.status = ? AND sellers.status = ?", models.ProductStatusPublished, models.SellerStatusApproved).
+		Where("skus.is_active = ?", true).
+		Where("EXISTS (SELECT 1 FROM inventories WHERE inventories.sku_id = skus.id AND inventories.on_hand > inventories.reserved)")
+	if categoryIDs != nil {
+		query = query.Where("products.category_id IN ?", categoryIDs)
+	}
+
+	columns := "skus.id AS sku_id, skus.product_id, skus.price_sell, skus.attributes, " +
+		"products.brand, products.category_id, products.created_at"
+	var vars []interface{}
+	var scores map[uint]float64
+	indexTruncated := false
+	if productSearch := search.NewProductSearch(text); productSearch != nil && cs.Index != nil {
+		ids, hitScores, total, err := cs.indexHits(search.Query{Text: text, CategoryIDs: categoryIDs, Limit: maxIndexHits})
+		if err != nil {
+			return nil, false, err
+		}
+		if len(ids) == 0 {
+			return nil, false, nil
+		}
+		scores = hitScores
+		indexTruncated = total > len(ids)
+		query = query.Where("products.id IN ?", ids)
+		if sortBy == SearchSortRelevance {
+			// Selected as a column like the MySQL relevance, for GORM
+			columns += ", FIELD(products.id" + strings.Repeat(", ?", len(ids)) + ") AS hit_rank"
+			for _, id := range ids {
+				vars = append(vars, id)
+			}
+			query = query.Order("hit_rank ASC")
+		}
+	} else if productSearch != nil {
+		productSearch.ActiveSKUsOnly = true
+		query = productSearch.Filter(query)
+		relevance, relevanceVars := productSearch.Relevance()
+		columns += ", (" + relevance + ") AS relevance"
+		vars = relevanceVars
+		if sortBy == SearchSortRelevance {
+			query = query.Order("relevance DESC")
+		}
+	}
+	switch sortBy {
+	case SearchSortPriceAsc:
+		query = query.Order("skus.price_sell ASC")
+	case SearchSortPriceDesc:
+		query = query.Order("skus.price_sell DESC")
+	}
+
+	var candidates []searchCandidate
+	err := query.Select(columns, vars...).
+		Order("products.created_at DESC, products.id DESC, skus.id ASC").
+		Limit(maxSearchCandidates + 1).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := indexTruncated || len(candidates) > maxSearchCandidates
+	if len(candidates) > maxSearchCandidates {
+		candidates = candidates[:maxSearchCandidates]
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		if scores != nil {
+			candidate.Relevance = scores[candidate.ProductID]
+		}
+		candidate.bucket = priceBucket(candidate.PriceSell)
+		candidate.attrs = map[string]string{}
+		var attrs models.Attributes
+		if len(candidate.Attributes) > 0 {
+			if err := json.Unmarshal(candidate.Attributes, &attrs); err != nil {
+				continue
+			}
+		}
+		for name, value := range attrs {
+			if text := facetText(value); text != "" {
+				candidate.attrs[name] = text
+			}
+		}
+	}
+	return candidates, truncated, nil
+}
+
+type productRating struct {
+	ProductID uint
+	Average   float64
+	Count     int
+}
+
+// Average rating of approved reviews per product
+func (cs *CatalogService) productRatings(productIDs []uint) (map[uint]productRating, error) {
+	var rows []productRating
+	err := cs.DB.Model(&models.Review{}).
+		Select("product_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("product_id IN ? AND status = ?", productIDs, models.ReviewStatusApproved).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make(map[uint]productRating, len(rows))
+	for _, row := range rows {
+		ratings[row.ProductID] = row
+	}
+	return ratings, nil
+}
+
+// Resolve the category filter to the IDs it covers (nil for the whole
+// catalog), the categories offered as facet, and a map from every covered
+// category to the facet category it is counted under
+func categoryScope(tree []CategoryTreeNode, categoryID *uint) ([]uint, []CategoryTreeNode, map[uint]uint, error) {
+	facetNodes := tree
+	var subtree []uint
+	if categoryID != nil {
+		node := findCategoryNode(tree, *categoryID)
+		if node == nil {
+			return nil, nil, nil, ErrSearchCategory
+		}
+		facetNodes = node.Children
+		subtree = append(subtree, node.ID)
+		collectCategoryIDs(node.Children, &subtree)
+	}
+
+	facetOf := make(map[uint]uint)
+	for _, facet := range facetNodes {
+		ids := []uint{facet.ID}
+		collectCategoryIDs(facet.Children, &ids)
+		for _, id := range ids {
+			facetOf[id] = facet.ID
+		}
+	}
+	return subtree, facetNodes, facetOf, nil
+}
+
+func findCategoryNode(nodes []CategoryTreeNode, id uint) *CategoryTreeNode {
+	for i := range nodes {
+		if nodes[i].ID == id {
+			return &nodes[i]
+		}
+		if found := findCategoryNode(nodes[i].Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func collectCategoryIDs(nodes []CategoryTreeNode, ids *[]uint) {
+	for _, node := range nodes {
+		*ids = append(*ids, node.ID)
+		collectCategoryIDs(node.Children, ids)
+	}
+}
+
+// Facet names used to tell which filter a candidate failed
+const (
+	facetBrand = "brand"
+	facetPrice = "price"
+)
+
+type searchFilter struct {
+	brands   []string
+	prices   []string
+	priceMin *decimal.Decimal
+	priceMax *decimal.Decimal
+	attrs    map[string][]string
+}
+
+func newSearchFilter(req *SearchRequest) *searchFilter {
+	return &searchFilter{
+		brands:   req.Brands,
+		prices:   req.Prices,
+		priceMin: req.PriceMin,
+		priceMax: req.PriceMax,
+		attrs:    req.Attributes,
+	}
+}
+
+// Names of the filters a candidate does not pass
+func (f *searchFilter) failures(candidate *searchCandidate) []string {
+	var failed []string
+	if len(f.brands) > 0 && !containsFold(f.brands, candidate.Brand) {
+		failed = append(failed, facetBrand)
+	}
+	if (len(f.prices) > 0 && !contains(f.prices, candidate.bucket)) ||
+		(f.priceMin != nil && candidate.PriceSell.LessThan(*f.priceMin)) ||
+		(f.priceMax != nil && candidate.PriceSell.GreaterThan(*f.priceMax)) {
+		failed = append(failed, facetPrice)
+	}
+	for name, values := range f.attrs {
+		if len(values) > 0 && !containsFold(values, candidate.attrs[name]) {
+			failed = append(failed, searchAttributePrefix+name)
+		}
+	}
+	return failed
+}
+
+// facetCounter counts distinct products per facet value
+type facetCounter struct {
+	fold     bool                     // Values differing only in case are counted as one
+	products map[string]map[uint]bool // By key, the value itself or lower-cased when folding
+	labels   map[string]string        // Value shown for each key, the first one seen
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{
+		products: make(map[string]map[uint]bool),
+		labels:   make(map[string]string),
+	}
+}
+
+// A counter for free-text values such as brands, where "Nike" and "NIKE"
+// are the same facet value
+func newFoldedFacetCounter() *facetCounter {
+	fc := newFacetCounter()
+	fc.fold = true
+	return fc
+}
+
+func (fc *facetCounter) key(value string) string {
+	if fc.fold {
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+func (fc *facetCounter) add(value string, productID uint) {
+	if value == "" {
+		return
+	}
+	key := fc.key(value)
+	if fc.products[key] == nil {
+		fc.products[key] = make(map[uint]bool)
+		fc.labels[key] = value
+	}
+	fc.products[key][productID] = true
+}
+
+func (fc *facetCounter) count(value string) int {
+	return len(fc.products[fc.key(value)])
+}
+
+// Facet values by count, or in price bucket order; selected values are
+// always listed so the buyer can deselect them
+func (fc *facetCounter) values(selected []string, bucketOrder bool) []FacetValue {
+	for _, value := range selected {
+		if key := fc.key(value); fc.products[key] == nil {
+			fc.products[key] = map[uint]bool{}
+			fc.labels[key] = value
+		}
+	}
+
+	values := make([]FacetValue, 0, len(fc.products))
+	for key, products := range fc.products {
+		value := fc.labels[key]
+		values = append(values, FacetValue{
+			Value:    value,
+			Count:    len(products),
+			Selected: containsFold(selected, value),
+		})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if bucketOrder {
+			return bucketIndex(values[i].Value) < bucketIndex(values[j].Value)
+		}
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	return values
+}
+
+func sortSearchHits(hits []*searchCandidate, sortBy string, ratings map[uint]productRating) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		switch sortBy {
+		case SearchSortRelevance:
+			if a.Relevance != b.Relevance {
+				return a.Relevance > b.Relevance
+			}
+		case SearchSortPriceAsc:
+			if !a.PriceSell.Equal(b.PriceSell) {
+				return a.PriceSell.LessThan(b.PriceSell)
+			}
+		case SearchSortPriceDesc:
+			if !a.PriceSell.Equal(b.PriceSell) {
+				return a.PriceSell.GreaterThan(b.PriceSell)
+			}
+		case SearchSortRating:
+			ra, rb := ratings[a.ProductID], ratings[b.ProductID]
+			if ra.Average != rb.Average {
+				return ra.Average > rb.Average
+			}
+			if ra.Count != rb.Count {
+				return ra.Count > rb.Count
+			}
+		}
+		// Newest first, then by ID so paging is stable
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ProductID > b.ProductID
+	})
+}
+
+// priceBucket names the bucket of a price, e.g. "500-1000" or "10000-"
+func priceBucket(price decimal.Decimal) string {
+	for i := len(priceBuckets) - 1; i >= 0; i-- {
+		if price.GreaterThanOrEqual(decimal.NewFromInt(priceBuckets[i])) {
+			return bucketName(i)
+		}
+	}
+	return bucketName(0)
+}
+
+func bucketName(i int) string {
+	name := strconv.FormatInt(priceBuckets[i], 10) + "-"
+	if i+1 < len(priceBuckets) {
+		name += strconv.FormatInt(priceBuckets[i+1], 10)
+	}
+	return name
+}
+
+func bucketIndex(name string) int {
+	for i := range priceBuckets {
+		if bucketName(i) == name {
+			return i
+		}
+	}
+	return len(priceBuckets)
+}
+
+func isPriceBucket(name string) bool {
+	return bucketIndex(name) < len(priceBuckets)
+}
+
+// Attribute values are compared as text; numbers and booleans are
+// formatted the way buyers would type them
+func facetText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// ParseAttributeFilters collects the "attr.<name>" query parameters
+func ParseAttributeFilters(query url.Values) map[string][]string {
+	attrs := make(map[string][]string)
+	for key, values := range query {
+		if name := strings.TrimPrefix(key, searchAttributePrefix); name != key && name != "" {
+			attrs[name] = values
+		}
+	}
+	return attrs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]*facetCounter) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Request DTOs
+type SearchRequest struct {
+	Query      string              `form:"q" binding:"max=200"`
+	CategoryID *uint               `form:"category_id"`
+	Brands     []string            `form:"brand"`
+	Prices     []string            `form:"price"` // Price buckets, e.g. 500-1000
+	PriceMin   *decimal.Decimal    `form:"-"`
+	PriceMax   *decimal.Decimal    `form:"-"`
+	Attributes map[string][]string `form:"-"` // From attr.<name> parameters
+	Sort       string              `form:"sort"`
+	Page       int                 `form:"page,default=1" binding:"min=1"`
+	Limit      int                 `form:"limit,default=20" binding:"min=1,max=100"`
+	Client     string              `form:"-"` // Client IP, for search logging
+}
+
+// Response DTOs
+type SearchResult struct {
+	Products  []SearchHit  `json:"products"`
+	Total     int64        `json:"total"`
+	Page      int          `json:"page"`
+	Limit     int          `json:"limit"`
+	Facets    SearchFacets `json:"facets"`
+	Truncated bool         `json:"truncated"` // Too many matches, total and facets only count the best ones
+}
+
+type SearchHit struct {
+	ProductResponse
+	PriceFrom   decimal.Decimal `json:"price_from"` // Cheapest SKU matching the filters
+	Rating      float64         `json:"rating"`
+	ReviewCount int             `json:"review_count"`
+}
+
+type SearchFacets struct {
+	Categories []CategoryFacet  `json:"categories"`
+	Brands     []FacetValue     `json:"brands"`
+	Prices     []FacetValue     `json:"prices"`
+	Attributes []AttributeFacet `json:"attributes"`
+}
+
+type FacetValue struct {
+	Value    string `json:"value"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+type CategoryFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int    `json:"count"`
+}
+
+type AttributeFacet struct {
+	Name   string       `json:"name"`
+	Values []FacetValue `json:"values"`
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+)
+
+// Searches from this far back count towards popular query suggestions
+const popularQueryWindow = 30 * 24 * time.Hour
+
+// A past search is only suggested once this many different clients made
+// it, so one buyer cannot put their text in front of everybody else
+const minQueryClients = 3
+
+// Popular searches considered before blocked ones are left out
+const suggestQueryCandidates = 50
+
+// Logged searches are pruned in batches of this size
+const pruneBatchSize = 5000
+
+// Products considered for title suggestions before ranking
+const suggestCandidates = 50
+
+// How a suggestion matches what the buyer typed, better matches first
+const (
+	matchPrefix     = iota // The suggestion starts with the text
+	matchWordPrefix        // A later word starts with the text
+	matchOther             // Matched by the search engine, e.g. via a synonym
+)
+
+// Suggest completions for a partially typed search: popular past searches,
+// product titles, brands and categories. Each group is ranked by how well
+// it matches, then by popularity.
+func (cs *CatalogService) Suggest(req *SuggestRequest) (*Suggestions, error) {
+	text := search.NormalizeQuery(req.Query)
+	result := &Suggestions{
+		Queries:    make([]QuerySuggestion, 0),
+		Products:   make([]ProductSuggestion, 0),
+		Brands:     make([]BrandSuggestion, 0),
+		Categories: make([]CategorySuggestion, 0),
+	}
+	if text == "" {
+		return result, nil
+	}
+
+	var err error
+	if result.Queries, err = cs.suggestQueries(text, req.Limit); err != nil {
+		return nil, err
+	}
+	if result.Products, err = cs.suggestProducts(text, req.Limit); err != nil {
+		return nil, err
+	}
+	if result.Brands, err = cs.suggestBrands(text, req.Limit); err != nil {
+		return nil, err
+	}
+	if result.Categories, err = cs.suggestCategories(text, req.Limit); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Past searches starting with text that found something and were made by
+// enough different clients, most widespread first. Searches containing a
+// prohibited keyword are never suggested.
+func (cs *CatalogService) suggestQueries(text string, limit int) ([]QuerySuggestion, error) {
+	var candidates []QuerySuggestion
+	err := cs.DB.Model(&models.SearchQuery{}).
+		Select("query AS text, COUNT(*) AS searches").
+		Where("query LIKE ? AND results > 0 AND created_at > ?", search.LikePrefix(text), time.Now().Add(-popularQueryWindow)).
+		Group("query").
+		Having("COUNT(DISTINCT client_hash) >= ?", minQueryClients).
+		Order("COUNT(DISTINCT client_hash) DESC, searches DESC, query ASC").
+		Limit(suggestQueryCandidates).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var keywords []string
+	if len(candidates) > 0 {
+		if err := cs.DB.Model(&models.ProhibitedKeyword{}).Pluck("keyword", &keywords).Error; err != nil {
+			return nil, err
+		}
+	}
+	prohibited := search.NewKeywordMatcher(keywords)
+	suggestions := make([]QuerySuggestion, 0, limit)
+	for _, candidate := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+		if !prohibited.Contains(candidate.Text) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions, nil
+}
+
+// Titles of visible products matching text, ranked by match and then by
+// approved review count, our best popularity signal on the marketplace
+func (cs *CatalogService) suggestProducts(text string, limit int) ([]ProductSuggestion, error) {
+	productSearch := search.NewProductSearch(text)
+	query := cs.visibleProducts()
+	var hits []uint
+	if cs.Index != nil {
+		var err error
+		if hits, _, _, err = cs.indexHits(search.Query{Text: text, Limit: suggestCandidates}); err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			return make([]ProductSuggestion, 0), nil
+		}
+		query = query.Where("products.id IN ?", hits)
+	} else {
+		productSearch.ActiveSKUsOnly = true
+		query = productSearch.OrderByRelevance(productSearch.Filter(query)).Limit(suggestCandidates)
+	}
+
+	var products []models.Product
+	if err := query.Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return make([]ProductSuggestion, 0), nil
+	}
+	if cs.Index != nil {
+		rank := make(map[uint]int, len(products))
+		for i, hit := range hits {
+			rank[hit] = i
+		}
+		sort.Slice(products, func(i, j int) bool {
+			return rank[products[i].ID] < rank[products[j].ID]
+		})
+	}
+
+	ids := make([]uint, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+	ratings, err := cs.productRatings(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]ProductSuggestion, 0, len(products))
+	for _, product := range products {
+		suggestions = append(suggestions, ProductSuggestion{
+			ID:          product.ID,
+			Title:       product.Title,
+			ReviewCount: ratings[product.ID].Count,
+			match:       matchKind(product.Title, text),
+		})
+	}
+	// Candidates arrive in relevance order, which breaks remaining ties
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].match != suggestions[j].match {
+			return suggestions[i].match < suggestions[j].match
+		}
+		return suggestions[i].ReviewCount > suggestions[j].ReviewCount
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// Brands starting with text, by number of visible products
+func (cs *CatalogService) suggestBrands(text string, limit int) ([]BrandSuggestion, error) {
+	suggestions := make([]BrandSuggestion, 0)
+	err := cs.visibleProducts().
+		Select("products.brand AS name, COUNT(*) AS product_count").
+		Where("products.brand LIKE ?", search.LikePrefix(text)).
+		Group("products.brand").
+		Order("product_count DESC, products.brand ASC").
+		Limit(limit).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
+
+// Active categories whose name or one of its words starts with text, by
+// number of visible products in the category and its subcategories
+func (cs *CatalogService) suggestCategories(text string, limit int) ([]CategorySuggestion, error) {
+	tree, err := cs.GetCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+
+	type matchedCategory struct {
+		node    *CategoryTreeNode
+		match   int
+		subtree []uint
+	}
+	var matches []matchedCategory
+	var allIDs []uint
+	var walk func(nodes []CategoryTreeNode)
+	walk = func(nodes []CategoryTreeNode) {
+		for i := range nodes {
+			node := &nodes[i]
+			if match := matchKind(node.Name, text); match != matchOther {
+				subtree := []uint{node.ID}
+				collectCategoryIDs(node.Children, &subtree)
+				matches = append(matches, matchedCategory{node: node, match: match, subtree: subtree})
+				allIDs = append(allIDs, subtree...)
+			}
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	suggestions := make([]CategorySuggestion, 0)
+	if len(matches) == 0 {
+		return suggestions, nil
+	}
+
+	var rows []struct {
+		CategoryID uint
+		Count      int
+	}
+	err = cs.visibleProducts().
+		Select("products.category_id, COUNT(*) AS count").
+		Where("products.category_id IN ?", allIDs).
+		Group("products.category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+
+	for _, matched := range matches {
+		suggestion := CategorySuggestion{
+			ID:    matched.node.ID,
+			Name:  matched.node.Name,
+			Slug:  matched.node.Slug,
+			match: matched.match,
+		}
+		for _, id := range matched.subtree {
+			suggestion.ProductCount += counts[id]
+		}
+		if suggestion.ProductCount > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.match != b.match {
+			return a.match < b.match
+		}
+		if a.ProductCount != b.ProductCount {
+			return a.ProductCount > b.ProductCount
+		}
+		return a.Name < b.Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// Log a buyer search with its result count and a hash of the client.
+// Logging is best effort, a failure must not fail the search.
+func (cs *CatalogService) logQuery(text string, results int64, client string) {
+	query := search.NormalizeQuery(text)
+	if query == "" {
+		return
+	}
+	entry := &models.SearchQuery{Query: query, Results: results, ClientHash: clientHash(client)}
+	if err := cs.DB.Create(entry).Error; err != nil {
+		log.Printf("Failed to log search query: %v", err)
+	}
+}
+
+// clientHash tells clients apart without storing their IP; the key keeps
+// the hash from being reversed by hashing every address
+func clientHash(client string) string {
+	if client == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, clientHashKey())
+	mac.Write([]byte(client))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// clientHashKey is the configured search client key or, when there is none,
+// a key derived from the JWT secret, so the signing secret itself is only
+// ever used to sign tokens
+func clientHashKey() []byte {
+	if key := config.AppConfig.SearchClientKey; key != "" {
+		return []byte(key)
+	}
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte("search client hash"))
+	return mac.Sum(nil)
+}
+
+// PruneSearchQueries deletes logged searches older than the retention
+// period, in batches so the table is not locked for long
+func (cs *CatalogService) PruneSearchQueries() error {
+	cutoff := time.Now().Add(-config.AppConfig.SearchQueryRetention)
+	pruned := int64(0)
+	for {
+		result := cs.DB.Where("created_at < ?", cutoff).Limit(pruneBatchSize).Delete(&models.SearchQuery{})
+		if result.Error != nil {
+			return result.Error
+		}
+		pruned += result.RowsAffected
+		if result.RowsAffected < pruneBatchSize {
+			break
+		}
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d logged search queries", pruned)
+	}
+	return nil
+}
+
+// StartSearchQueryPruner prunes logged searches now and then every
+// interval
+func (cs *CatalogService) StartSearchQueryPruner(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := cs.PruneSearchQueries(); err != nil {
+				log.Printf("Search query pruning failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// matchKind tells how value matches the typed text, ignoring case
+func matchKind(value, text string) int {
+	value = strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(value, text):
+		return matchPrefix
+	case strings.Contains(value, " "+text):
+		return matchWordPrefix
+	default:
+		return matchOther
+	}
+}
+
+// Request DTOs
+type SuggestRequest struct {
+	Query string `form:"q" binding:"max=100"`
+	Limit int    `form:"limit,default=5" binding:"min=1,max=10"` // Per group
+}
+
+// Response DTOs
+type Suggestions struct {
+	Queries    []QuerySuggestion    `json:"queries"`
+	Products   []ProductSuggestion  `json:"products"`
+	Brands     []BrandSuggestion    `json:"brands"`
+	Categories []CategorySuggestion `json:"categories"`
+}
+
+type QuerySuggestion struct {
+	Text     string `json:"text"`
+	Searches int    `json:"searches"`
+}
+
+type ProductSuggestion struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	ReviewCount int    `json:"review_count"`
+
+	match int
+}
+
+type BrandSuggestion struct {
+	Name         string `json:"name"`
+	ProductCount int    `json:"product_count"`
+}
+
+type CategorySuggestion struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ProductCount int    `json:"product_count"`
+
+	match int
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Attributes holds free-form product or SKU attributes, validated against
+// the category's AttributesSchema. Values are strings, numbers or booleans.
+type Attributes map[string]interface{}
+
+// String returns the attribute value formatted as text, or "" if unset
+func (a Attributes) String(name string) string {
+	value, ok := a[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func unmarshalAttributes(data json.RawMessage) (Attributes, error) {
+	attrs := Attributes{}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &attrs); err != nil {
+			return attrs, err
+		}
+	}
+	return attrs, nil
+}
+
+func marshalAttributes(attrs Attributes) (json.RawMessage, error) {
+	if attrs == nil {
+		attrs = Attributes{}
+	}
+	return json.Marshal(attrs)
+}
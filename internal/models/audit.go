@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -14,3 +15,8 @@ type AuditLog struct {
 	Meta      json.RawMessage `gorm:"type:json"`
 	CreatedAt time.Time
 }
+
+// UserActor formats a user ID as an AuditLog actor
+func UserActor(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
     "time"
+    "sort"
     "encoding/json"
 )
 
@@ -10,9 +11,13 @@ type Category struct {
     ParentID         *uint           `json:"parent_id"`
     Name             string          `gorm:"not null" json:"name"`
     AttributesSchema json.RawMessage `gorm:"type:json" json:"attributes_schema"`
-    SEOSlug          string          `json:"seo_slug"`
+    SchemaVersion    int             `gorm:"default:0" json:"schema_version"`
+    ScoringRules     json.RawMessage `gorm:"type:json" json:"scoring_rules,omitempty"` // Inherited from the parent when empty
+    SEOSlug          string          `gorm:"size:191;uniqueIndex" json:"seo_slug"`
     IsActive         bool            `gorm:"default:true" json:"is_active"`
+    Sort             int             `gorm:"default:0" json:"sort"` // Position among siblings
     CreatedAt        time.Time       `json:"created_at"`
+    UpdatedAt        time.Time       `json:"updated_at"`
     
     // Relations
     Parent           *Category       `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
@@ -20,16 +25,85 @@ type Category struct {
     Products         []Product       `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
 }
 
+// CategorySchemaVersion keeps every attribute schema a category has had,
+// so products validated against an older version can be traced
+type CategorySchemaVersion struct {
+    ID         uint            `gorm:"primaryKey" json:"id"`
+    CategoryID uint            `gorm:"not null;uniqueIndex:idx_category_schema_version" json:"category_id"`
+    Version    int             `gorm:"not null;uniqueIndex:idx_category_schema_version" json:"version"`
+    Schema     json.RawMessage `gorm:"type:json" json:"schema"`
+    CreatedBy  uint            `json:"created_by"`
+    CreatedAt  time.Time       `json:"created_at"`
+}
+
 // Category attribute schema helper
 type AttributeDefinition struct {
     Name        string   `json:"name"`
     Type        string   `json:"type"`        // text, select, number, boolean
     Required    bool     `json:"required"`
     Options     []string `json:"options,omitempty"` // For select type
-    Validation  string   `json:"validation,omitempty"`
+    Validation  string   `json:"validation,omitempty"` // Regex the value must match
+    Variant     bool     `json:"variant,omitempty"`    // Set per SKU rather than per product
 }
 
+// Attribute types
+const (
+    AttributeTypeText    = "text"
+    AttributeTypeSelect  = "select"
+    AttributeTypeNumber  = "number"
+    AttributeTypeBoolean = "boolean"
+)
+
 type CategorySchema struct {
     Attributes []AttributeDefinition `json:"attributes"`
 }
 
+
+// GetSchema decodes the category's attribute schema; a category without a
+// schema accepts any attributes
+func (c *Category) GetSchema() (*CategorySchema, error) {
+    var schema CategorySchema
+    if len(c.AttributesSchema) > 0 && string(c.AttributesSchema) != "null" {
+        if err := json.Unmarshal(c.AttributesSchema, &schema); err != nil {
+            return nil, err
+        }
+    }
+    return &schema, nil
+}
+
+// BuildCategoryTree nests a flat category list by ParentID, ordering
+// siblings by Sort then Name. Unless includeInactive is set, inactive
+// categories are dropped together with their whole subtree.
+func BuildCategoryTree(categories []Category, includeInactive bool) []Category {
+    byParent := make(map[uint][]Category)
+    for _, category := range categories {
+        var parentID uint
+        if category.ParentID != nil {
+            parentID = *category.ParentID
+        }
+        byParent[parentID] = append(byParent[parentID], category)
+    }
+    
+    var build func(parentID uint) []Category
+    build = func(parentID uint) []Category {
+        children := byParent[parentID]
+        sort.SliceStable(children, func(i, j int) bool {
+            if children[i].Sort != children[j].Sort {
+                return children[i].Sort < children[j].Sort
+            }
+            return children[i].Name < children[j].Name
+        })
+        
+        nodes := make([]Category, 0, len(children))
+        for _, child := range children {
+            if !child.IsActive && !includeInactive {
+                continue
+            }
+            child.Children = build(child.ID)
+            nodes = append(nodes, child)
+        }
+        return nodes
+    }
+    
+    return build(0)
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ExportJob tracks a background catalog export of a seller's products
+type ExportJob struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	SellerID         uint       `gorm:"not null;index" json:"seller_id"`
+	UserID           uint       `gorm:"not null" json:"user_id"`
+	Format           string     `gorm:"size:10;not null" json:"format"` // csv, jsonl
+	FilterStatus     *int       `json:"filter_status,omitempty"`
+	FilterCategoryID *uint      `json:"filter_category_id,omitempty"`
+	Status           int        `gorm:"default:0;index" json:"status"` // 0=pending, 1=processing, 2=completed, 3=failed
+	ProductCount     int        `json:"product_count"`
+	RowCount         int        `json:"row_count"` // CSV rows, or products for jsonl
+	ObjectName       string     `json:"-"`
+	Error            string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt        *time.Time `json:"started_at,omitempty"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Export job status constants
+const (
+	ExportStatusPending = iota
+	ExportStatusProcessing
+	ExportStatusCompleted
+	ExportStatusFailed
+)
+
+// Export formats
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ImportJob tracks an asynchronous bulk product import from a CSV or XLSX
+// file with one row per SKU
+type ImportJob struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	SellerID        uint       `gorm:"not null;index" json:"seller_id"`
+	UserID          uint       `gorm:"not null" json:"user_id"`
+	FileName        string     `gorm:"not null" json:"file_name"`
+	Format          string     `gorm:"size:10;not null" json:"format"` // csv, xlsx
+	SourceObject    string     `gorm:"not null" json:"-"`
+	Status          int        `gorm:"default:0;index" json:"status"` // 0=pending, 1=processing, 2=completed, 3=failed
+	TotalRows       int        `json:"total_rows"`
+	ProcessedRows   int        `json:"processed_rows"`
+	FailedRows      int        `json:"failed_rows"`
+	ProductsCreated int        `json:"products_created"`
+	ProductsUpdated int        `json:"products_updated"`                 // Matched by SKU code, e.g. when importing an export
+	ErrorReport     string     `json:"-"`                                // Object name of the row error report
+	Error           string     `gorm:"type:text" json:"error,omitempty"` // Why the whole job failed
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Import job status constants
+const (
+	ImportStatusPending = iota
+	ImportStatusProcessing
+	ImportStatusCompleted
+	ImportStatusFailed
+)
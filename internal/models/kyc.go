@@ -3,11 +3,51 @@ package models
 import "time"
 
 type KYC struct {
-	ID          uint   `gorm:"primaryKey"`
-	SellerID    uint   `gorm:"not null"`
-	Type        string // PAN, GSTIN, etc.
-	DocumentURL string
-	Status      int `gorm:"default:0"` // 0=pending, 1=approved, 2=rejected
-	Remarks     string
-	CreatedAt   time.Time
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SellerID    uint       `gorm:"not null" json:"seller_id"`
+	Type        string     `json:"type"` // PAN, GSTIN, etc.
+	DocumentURL string     `json:"-"`    // bucket path, only ever shared as a presigned URL
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	Status      int        `gorm:"default:0" json:"status"` // 0=pending, 1=approved, 2=rejected, 3=superseded
+	Remarks     string     `json:"remarks"`
+	ReviewedBy  *uint      `json:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// KYC status constants
+const (
+	KYCStatusPending = iota
+	KYCStatusApproved
+	KYCStatusRejected
+	KYCStatusSuperseded
+)
+
+// KYC document types
+const (
+	KYCTypePAN             = "PAN"
+	KYCTypeGSTIN           = "GSTIN"
+	KYCTypeCancelledCheque = "CANCELLED_CHEQUE"
+	KYCTypeAddressProof    = "ADDRESS_PROOF"
+
+	// Business documents, kept in the seller-documents bucket
+	KYCTypeBusinessRegistration = "BUSINESS_REGISTRATION"
+	KYCTypeTrademarkCertificate = "TRADEMARK_CERTIFICATE"
+)
+
+// IsValidKYCType reports whether t is a supported KYC document type
+func IsValidKYCType(t string) bool {
+	switch t {
+	case KYCTypePAN, KYCTypeGSTIN, KYCTypeCancelledCheque, KYCTypeAddressProof,
+		KYCTypeBusinessRegistration, KYCTypeTrademarkCertificate:
+		return true
+	}
+	return false
+}
+
+// IsBusinessDocument reports whether t is a business document rather than
+// an identity document
+func IsBusinessDocument(t string) bool {
+	return t == KYCTypeBusinessRegistration || t == KYCTypeTrademarkCertificate
 }
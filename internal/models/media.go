@@ -1,14 +1,57 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
+// Media stores the "/bucket/object" paths of its files. Links expire, so
+// URL and Variants are filled in when media is read, with
+// storage.ResolveMediaLinks.
 type Media struct {
-	ID         uint   `gorm:"primaryKey"`
-	EntityType string `gorm:"not null"` // product, review, etc.
-	EntityID   uint   `gorm:"not null"`
-	URL        string `gorm:"not null"`
-	Type       string // image, video
-	AltText    string
-	Sort       int `gorm:"default:0"`
-	CreatedAt  time.Time
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	EntityType   string          `gorm:"not null" json:"entity_type"` // product, review, etc.
+	EntityID     uint            `gorm:"not null" json:"entity_id"`
+	Path         string          `gorm:"column:url;not null" json:"-"`
+	Type         string          `json:"type"` // image, video
+	AltText      string          `json:"alt_text"`
+	Width        int             `json:"width,omitempty"`
+	Height       int             `json:"height,omitempty"`
+	VariantPaths json.RawMessage `gorm:"column:variants;type:json" json:"-"` // {"thumbnail": path, "listing": path, ...}
+	Sort         int             `gorm:"default:0" json:"sort"`
+	CreatedAt    time.Time       `json:"created_at"`
+
+	// Links to the stored files
+	URL      string            `gorm:"-" json:"url"`
+	Variants map[string]string `gorm:"-" json:"variants,omitempty"`
+}
+
+// Media types
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
+// Media entity types, matching the owner table name GORM uses for
+// polymorphic relations
+const (
+	MediaEntityProduct = "products"
+)
+
+func (m *Media) GetVariantPaths() (map[string]string, error) {
+	paths := map[string]string{}
+	if len(m.VariantPaths) > 0 {
+		err := json.Unmarshal(m.VariantPaths, &paths)
+		return paths, err
+	}
+	return paths, nil
+}
+
+func (m *Media) SetVariantPaths(paths map[string]string) error {
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return err
+	}
+	m.VariantPaths = data
+	return nil
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProductModeration records one status transition of a product, who made
+// it and why
+type ProductModeration struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	ProductID  uint            `gorm:"not null;index" json:"product_id"`
+	FromStatus int             `json:"from_status"`
+	ToStatus   int             `json:"to_status"`
+	Action     string          `gorm:"not null" json:"action"`
+	Reasons    json.RawMessage `gorm:"type:json" json:"reasons,omitempty"`
+	Note       string          `gorm:"type:text" json:"note,omitempty"`
+	ActorID    *uint           `json:"actor_id"` // nil for automatic checks
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Moderation actions
+const (
+	ModerationActionSubmit     = "submit"
+	ModerationActionAutoReject = "auto_reject"
+	ModerationActionApprove    = "approve"
+	ModerationActionReject     = "reject"
+	ModerationActionEdit       = "edit"
+	ModerationActionArchive    = "archive"
+	ModerationActionUnarchive  = "unarchive"
+)
+
+// RejectionReason is a structured reason shown to the seller
+type RejectionReason struct {
+	Code    string `json:"code" binding:"required"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message" binding:"required,max=500"`
+}
+
+// Rejection reason codes
+const (
+	RejectionProhibitedKeyword = "prohibited_keyword"
+	RejectionRestrictedBrand   = "restricted_brand"
+	RejectionPoorImages        = "poor_images"
+	RejectionWrongCategory     = "wrong_category"
+	RejectionMisleadingContent = "misleading_content"
+	RejectionPricing           = "pricing"
+	RejectionCounterfeit       = "counterfeit"
+	RejectionLowScore          = "low_score"
+	RejectionOther             = "other"
+)
+
+// IsValidRejectionCode reports whether code is a known rejection reason
+func IsValidRejectionCode(code string) bool {
+	switch code {
+	case RejectionProhibitedKeyword, RejectionRestrictedBrand, RejectionPoorImages, RejectionWrongCategory,
+		RejectionMisleadingContent, RejectionPricing, RejectionCounterfeit, RejectionLowScore, RejectionOther:
+		return true
+	}
+	return false
+}
+
+// ProhibitedKeyword blocks products mentioning it from publication
+type ProhibitedKeyword struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Keyword   string    `gorm:"size:191;uniqueIndex;not null" json:"keyword"` // Lower-case words separated by single spaces
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestrictedBrand may only be listed by sellers with a BrandAuthorization
+type RestrictedBrand struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Brand     string    `gorm:"size:191;uniqueIndex;not null" json:"brand"` // Stored lower-case
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Authorizations []BrandAuthorization `gorm:"foreignKey:RestrictedBrandID" json:"authorizations,omitempty"`
+}
+
+type BrandAuthorization struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	RestrictedBrandID uint      `gorm:"not null;uniqueIndex:idx_brand_seller" json:"restricted_brand_id"`
+	SellerID          uint      `gorm:"not null;uniqueIndex:idx_brand_seller" json:"seller_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
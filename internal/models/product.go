@@ -2,8 +2,8 @@ package models
 
 import (
     "time"
-    /*
     "encoding/json"
+    /*
     "github.com/shopspring/decimal"
     */
 )
@@ -12,11 +12,14 @@ type Product struct {
     ID          uint            `gorm:"primaryKey" json:"id"`
     SellerID    uint            `gorm:"not null" json:"seller_id"`
     CategoryID  uint            `gorm:"not null" json:"category_id"`
-    Title       string          `gorm:"not null" json:"title"`
-    Description string          `gorm:"type:text" json:"description"`
-    Brand       string          `json:"brand"`
-    Status      int             `gorm:"default:0" json:"status"` // 0=draft, 1=published, 2=rejected
+    Title       string          `gorm:"not null;index:idx_product_search,class:FULLTEXT" json:"title"`
+    Description string          `gorm:"type:text;index:idx_product_search,class:FULLTEXT" json:"description"`
+    Brand       string          `gorm:"index:idx_product_search,class:FULLTEXT" json:"brand"`
+    Attributes  json.RawMessage `gorm:"type:json" json:"attributes"` // Category attributes shared by all SKUs
+    Status      int             `gorm:"default:0" json:"status"` // 0=draft, 1=published, 2=rejected, 3=archived, 4=pending review
     Score       int             `gorm:"default:0" json:"score"`  // Content quality score
+    Rejection   json.RawMessage `gorm:"type:json" json:"rejection_reasons,omitempty"` // Reasons of the latest rejection
+    EditedSinceRejection bool   `gorm:"default:false" json:"edited_since_rejection"` // A rejected product can be resubmitted once edited
     CreatedAt   time.Time       `json:"created_at"`
     UpdatedAt   time.Time       `json:"updated_at"`
     
@@ -32,5 +35,20 @@ const (
     ProductStatusDraft = iota
     ProductStatusPublished
     ProductStatusRejected
+    ProductStatusArchived
+    ProductStatusPendingReview
 )
 
+
+func (p *Product) GetAttributes() (Attributes, error) {
+    return unmarshalAttributes(p.Attributes)
+}
+
+func (p *Product) SetAttributes(attrs Attributes) error {
+    data, err := marshalAttributes(attrs)
+    if err != nil {
+        return err
+    }
+    p.Attributes = data
+    return nil
+}
@@ -12,6 +12,12 @@ type Review struct {
 	Rating    int  `gorm:"not null"`
 	Text      string
 	Media     json.RawMessage `gorm:"type:json"`
-	Status    int             `gorm:"default:1"` // 1=approved, 0=pending
+	Status    int             `gorm:"default:1"`
 	CreatedAt time.Time
 }
+
+// Review status constants
+const (
+	ReviewStatusPending = iota
+	ReviewStatusApproved
+)
@@ -0,0 +1,64 @@
+package models
+
+import "encoding/json"
+
+// LengthRule scores a text field: full points when its length falls in
+// [IdealMin, IdealMax], partial points from MinLength upwards
+type LengthRule struct {
+	Points        int `json:"points"`
+	PartialPoints int `json:"partial_points"`
+	MinLength     int `json:"min_length"`
+	IdealMin      int `json:"ideal_min"`
+	IdealMax      int `json:"ideal_max"`
+}
+
+// ScoringRules configure the content quality score of the products in a
+// category. Rules set on a category apply to its whole subtree unless a
+// descendant sets its own.
+type ScoringRules struct {
+	MinPublishScore    int        `json:"min_publish_score"`
+	Title              LengthRule `json:"title"`
+	Description        LengthRule `json:"description"`
+	BrandPoints        int        `json:"brand_points"`
+	SKUPoints          int        `json:"sku_points"`      // At least one active SKU
+	DiscountPoints     int        `json:"discount_points"` // A SKU sells below its MRP
+	MediaPoints        int        `json:"media_points"`
+	MediaPartialPoints int        `json:"media_partial_points"` // At least one image
+	MinImages          int        `json:"min_images"`           // Images needed for full media points
+	AttributePoints    int        `json:"attribute_points"`     // Scaled by the share of schema attributes filled in
+}
+
+// MaxScore is the score of a product that meets every rule
+func (r *ScoringRules) MaxScore() int {
+	return r.Title.Points + r.Description.Points + r.BrandPoints + r.SKUPoints +
+		r.DiscountPoints + r.MediaPoints + r.AttributePoints
+}
+
+// DefaultScoringRules apply to categories without rules of their own
+func DefaultScoringRules() *ScoringRules {
+	return &ScoringRules{
+		MinPublishScore:    60,
+		Title:              LengthRule{Points: 20, PartialPoints: 10, MinLength: 5, IdealMin: 10, IdealMax: 100},
+		Description:        LengthRule{Points: 20, PartialPoints: 12, MinLength: 50, IdealMin: 100, IdealMax: 1000},
+		BrandPoints:        10,
+		SKUPoints:          15,
+		DiscountPoints:     10,
+		MediaPoints:        15,
+		MediaPartialPoints: 8,
+		MinImages:          3,
+		AttributePoints:    10,
+	}
+}
+
+// GetScoringRules decodes the rules set on the category itself, nil when
+// it inherits them
+func (c *Category) GetScoringRules() (*ScoringRules, error) {
+	if len(c.ScoringRules) == 0 || string(c.ScoringRules) == "null" {
+		return nil, nil
+	}
+	var rules ScoringRules
+	if err := json.Unmarshal(c.ScoringRules, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SearchEvent records a product change the marketplace search index has to
+// pick up. The APIs run as separate processes, so the index follows this
+// table instead of being called directly.
+type SearchEvent struct {
+	ID        uint      `gorm:"primaryKey"`
+	ProductID uint      `gorm:"not null;index"`
+	Type      string    `gorm:"size:16;not null"` // publish, update, unpublish
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// Search event types
+const (
+	SearchEventPublish   = "publish"
+	SearchEventUpdate    = "update"
+	SearchEventUnpublish = "unpublish"
+)
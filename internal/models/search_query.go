@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SearchQuery logs a buyer search, normalised, with how many products it
+// found. It feeds query suggestions and the zero-result report.
+type SearchQuery struct {
+	ID         uint      `gorm:"primaryKey"`
+	Query      string    `gorm:"size:200;not null;index"`
+	Results    int64     `gorm:"not null"`
+	ClientHash string    `gorm:"size:64"` // Keyed hash of the client IP, never the IP itself
+	CreatedAt  time.Time `gorm:"index"`
+}
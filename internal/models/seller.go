@@ -3,22 +3,56 @@ package models
 import "time"
 
 type Seller struct {
-	ID          uint   `gorm:"primaryKey"`
-	LegalName   string `gorm:"not null"`
-	DisplayName string
-	GSTIN       string
-	PAN         string
-	BankRef     string
-	Status      int `gorm:"default:0"` // 0=pending, 1=approved, 2=rejected
-	RiskScore   int `gorm:"default:0"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	LegalName     string     `gorm:"not null" json:"legal_name"`
+	DisplayName   string     `json:"display_name"`
+	GSTIN         string     `json:"gstin"`
+	PAN           string     `json:"pan"`
+	BankRef       string     `json:"bank_ref"`
+	Status        int        `gorm:"default:0" json:"status"` // 0=pending, 1=approved, 2=rejected
+	StatusRemarks string     `json:"status_remarks"`          // reviewer remarks for the last approve/reject
+	ReviewedBy    *uint      `json:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	RiskScore     int        `gorm:"default:0" json:"risk_score"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type SellerUser struct {
-	ID       uint `gorm:"primaryKey"`
-	SellerID uint `gorm:"not null"`
-	UserID   uint `gorm:"not null"`
-	Role     string
-	Status   int `gorm:"default:1"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	SellerID uint   `gorm:"not null" json:"seller_id"`
+	UserID   uint   `gorm:"not null" json:"user_id"`
+	Role     string `json:"role"`
+	Status   int    `gorm:"default:1" json:"status"` // 0=suspended, 1=active, 2=invited
+}
+
+// Seller status constants
+const (
+	SellerStatusPending = iota
+	SellerStatusApproved
+	SellerStatusRejected
+)
+
+// Seller user status constants
+const (
+	SellerUserStatusSuspended = iota
+	SellerUserStatusActive
+	SellerUserStatusInvited
+)
+
+// Seller user role constants
+const (
+	SellerRoleOwner          = "owner"
+	SellerRoleCatalogManager = "catalog_manager"
+	SellerRoleFulfilment     = "fulfilment"
+	SellerRoleFinance        = "finance"
+)
+
+// IsValidSellerRole reports whether role is one of the defined seller roles
+func IsValidSellerRole(role string) bool {
+	switch role {
+	case SellerRoleOwner, SellerRoleCatalogManager, SellerRoleFulfilment, SellerRoleFinance:
+		return true
+	}
+	return false
 }
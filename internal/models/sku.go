@@ -9,12 +9,13 @@ import (
 type SKU struct {
     ID         uint            `gorm:"primaryKey" json:"id"`
     ProductID  uint            `gorm:"not null" json:"product_id"`
-    SKUCode    string          `gorm:"unique;not null" json:"sku_code"`
-    Attributes json.RawMessage `gorm:"type:json" json:"attributes"` // {color: "red", size: "L"}
+    SellerID   uint            `gorm:"not null;uniqueIndex:idx_seller_sku_code" json:"seller_id"`
+    SKUCode    string          `gorm:"size:64;not null;uniqueIndex:idx_seller_sku_code" json:"sku_code"` // Unique per seller
+    Attributes json.RawMessage `gorm:"type:json" json:"attributes"` // {"color": "red", "size": "L"}
     PriceMRP   decimal.Decimal `gorm:"type:decimal(10,2)" json:"price_mrp"`
     PriceSell  decimal.Decimal `gorm:"type:decimal(10,2)" json:"price_sell"`
     TaxPct     decimal.Decimal `gorm:"type:decimal(5,2)" json:"tax_pct"`
-    Barcode    string          `json:"barcode"`
+    Barcode    string          `gorm:"size:14;index" json:"barcode"` // EAN-8, UPC-A or EAN-13
     IsActive   bool            `gorm:"default:true" json:"is_active"`
     CreatedAt  time.Time       `json:"created_at"`
     
@@ -23,29 +24,15 @@ type SKU struct {
     Inventory  []Inventory     `gorm:"foreignKey:SKUID" json:"inventory,omitempty"`
 }
 
-// SKU attribute helper
-type SKUAttributes struct {
-    Color    string `json:"color,omitempty"`
-    Size     string `json:"size,omitempty"`
-    Material string `json:"material,omitempty"`
-    Model    string `json:"model,omitempty"`
+func (s *SKU) GetAttributes() (Attributes, error) {
+    return unmarshalAttributes(s.Attributes)
 }
 
-func (s *SKU) GetAttributes() (*SKUAttributes, error) {
-    var attrs SKUAttributes
-    if len(s.Attributes) > 0 {
-        err := json.Unmarshal(s.Attributes, &attrs)
-        return &attrs, err
-    }
-    return &attrs, nil
-}
-
-func (s *SKU) SetAttributes(attrs SKUAttributes) error {
-    data, err := json.Marshal(attrs)
+func (s *SKU) SetAttributes(attrs Attributes) error {
+    data, err := marshalAttributes(attrs)
     if err != nil {
         return err
     }
     s.Attributes = data
     return nil
 }
-
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// UploadSession tracks a presigned direct upload into the temp-uploads
+// bucket until it is finalised into its permanent bucket
+type UploadSession struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SellerID    uint      `gorm:"not null;index" json:"seller_id"`
+	UserID      uint      `gorm:"not null" json:"user_id"`
+	Purpose     string    `gorm:"not null" json:"purpose"` // product_media, kyc_document
+	ProductID   *uint     `json:"product_id,omitempty"`
+	KYCType     string    `json:"kyc_type,omitempty"`
+	ObjectName  string    `gorm:"not null" json:"-"`
+	ContentType string    `gorm:"not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Status      int       `gorm:"default:0;index" json:"status"` // 0=pending, 1=finalised, 2=expired
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Upload session status constants
+const (
+	UploadStatusPending = iota
+	UploadStatusFinalised
+	UploadStatusExpired
+)
+
+// Upload purposes
+const (
+	UploadPurposeProductMedia = "product_media"
+	UploadPurposeKYCDocument  = "kyc_document"
+)
@@ -3,13 +3,53 @@ package models
 import "time"
 
 type User struct {
+	ID                  uint   `gorm:"primaryKey"`
+	Name                string `gorm:"not null"`
+	Email               string `gorm:"unique"`
+	Phone               string `gorm:"unique"`
+	PasswordHash        string
+	Role                string `gorm:"default:customer"`
+	Status              int    `gorm:"default:1"` // 1=active, 0=inactive
+	TwoFAEnabled        bool   `gorm:"default:false"`
+	TwoFASecret         string `gorm:"size:255"`  // AES-GCM encrypted TOTP secret
+	TwoFALastStep       int64  `gorm:"default:0"` // last accepted TOTP step, blocks code replay
+	FailedLoginAttempts int    `gorm:"default:0"`
+	LockedUntil         *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// User status constants
+const (
+	UserStatusInactive = iota
+	UserStatusActive
+)
+
+// User role constants
+const (
+	UserRoleCustomer = "customer"
+	UserRoleAdmin    = "admin"
+)
+
+// RefreshToken is a server-side record of an issued refresh token. Tokens
+// rotate on every use; all tokens descending from one login share a FamilyID
+// so a replayed token can revoke the whole chain.
+type RefreshToken struct {
 	ID           uint   `gorm:"primaryKey"`
-	Name         string `gorm:"not null"`
-	Email        string `gorm:"unique"`
-	Phone        string `gorm:"unique"`
-	PasswordHash string
-	Status       int  `gorm:"default:1"` // 1=active, 0=inactive
-	TwoFAEnabled bool `gorm:"default:false"`
+	UserID       uint   `gorm:"not null;index"`
+	TokenHash    string `gorm:"size:64;uniqueIndex;not null"`
+	FamilyID     string `gorm:"size:64;index;not null"`
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *uint
 	CreatedAt    time.Time
-	UpdatedAt    time.Time
+}
+
+// RecoveryCode is a single-use 2FA backup code, stored hashed
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
 }
@@ -0,0 +1,149 @@
+package search
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// stopWords carry no meaning in product searches and are left out of both
+// the index and queries
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "for": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// Analyzer turns text into index terms: lowercase words without stop
+// words, reduced to their stem, with configurable synonyms for queries
+type Analyzer struct {
+	synonyms map[string][]string
+}
+
+// NewAnalyzer builds an analyzer from synonym groups, each a list of
+// single words that mean the same, e.g. {"tv", "television"}
+func NewAnalyzer(groups [][]string) *Analyzer {
+	a := &Analyzer{synonyms: make(map[string][]string)}
+	for _, group := range groups {
+		var stems []string
+		for _, word := range group {
+			if terms := a.Terms(word); len(terms) == 1 {
+				stems = append(stems, terms[0])
+			}
+		}
+		for _, stem := range stems {
+			for _, other := range stems {
+				if other != stem && !contains(a.synonyms[stem], other) {
+					a.synonyms[stem] = append(a.synonyms[stem], other)
+				}
+			}
+		}
+	}
+	return a
+}
+
+// LoadSynonyms reads synonym groups from a file with one comma-separated
+// group per line. Blank lines and lines starting with # are skipped.
+func LoadSynonyms(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseSynonyms(file)
+}
+
+// ParseSynonyms reads synonym groups in the LoadSynonyms format
+func ParseSynonyms(r io.Reader) ([][]string, error) {
+	var groups [][]string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var group []string
+		for _, word := range strings.Split(line, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				group = append(group, word)
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups, scanner.Err()
+}
+
+// Terms tokenises and stems text, dropping stop words
+func (a *Analyzer) Terms(text string) []string {
+	words := Tokenize(text)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		terms = append(terms, Stem(word))
+	}
+	return terms
+}
+
+// Synonyms returns the other terms of the term's synonym group
+func (a *Analyzer) Synonyms(term string) []string {
+	return a.synonyms[term]
+}
+
+// Stem reduces an English word to a stem by stripping common plural and
+// verb suffixes. It is deliberately light: the same stem is produced for
+// the index and the query, so it only has to be consistent, not correct.
+func Stem(word string) string {
+	if len(word) <= 3 || !isAlpha(word) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ing") && len(word) > 5:
+		return undouble(word[:len(word)-3])
+	case strings.HasSuffix(word, "ed") && len(word) > 4:
+		return undouble(word[:len(word)-2])
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// undouble drops a doubled final consonant left by a stripped suffix, as
+// in "running" or "zipped"
+func undouble(stem string) string {
+	n := len(stem)
+	if n >= 2 && stem[n-1] == stem[n-2] && !strings.ContainsRune("aeioulsz", rune(stem[n-1])) {
+		return stem[:n-1]
+	}
+	return stem
+}
+
+func isAlpha(word string) bool {
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"shirts", "shirt"},
+		{"batteries", "battery"},
+		{"dresses", "dress"},
+		{"watches", "watch"},
+		{"brushes", "brush"},
+		{"boxes", "box"},
+		{"running", "run"},
+		{"rolling", "roll"},
+		{"zipped", "zip"},
+		{"glass", "glass"},
+		{"status", "status"},
+		{"tennis", "tennis"},
+		{"bus", "bus"},
+		{"used", "used"},
+		{"usb3", "usb3"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzerTerms(t *testing.T) {
+	a := NewAnalyzer(nil)
+
+	got := a.Terms("The Red T-Shirts, for Running!")
+	want := []string{"red", "t", "shirt", "run"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %q, want %q", got, want)
+	}
+
+	if got := a.Terms("the and of"); len(got) != 0 {
+		t.Errorf("Terms of stop words = %q, want none", got)
+	}
+}
+
+func TestAnalyzerSynonyms(t *testing.T) {
+	a := NewAnalyzer([][]string{
+		{"TV", "televisions"},
+		{"sofa", "couch", "settee"},
+		{"laptop", "notebook computer"}, // Phrases are not supported
+	})
+
+	if got, want := a.Synonyms("tv"), []string{"television"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Synonyms(tv) = %q, want %q", got, want)
+	}
+	if got, want := a.Synonyms("couch"), []string{"sofa", "settee"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Synonyms(couch) = %q, want %q", got, want)
+	}
+	if got := a.Synonyms("laptop"); len(got) != 0 {
+		t.Errorf("Synonyms(laptop) = %q, want none", got)
+	}
+}
+
+func TestParseSynonyms(t *testing.T) {
+	input := `# Synonyms
+tv, television
+
+sofa,couch , settee
+lonely
+`
+	got, err := ParseSynonyms(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"tv", "television"}, {"sofa", "couch", "settee"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSynonyms = %q, want %q", got, want)
+	}
+}
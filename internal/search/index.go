@@ -0,0 +1,107 @@
+package search
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/models"
+)
+
+// Supported marketplace search backends
+const (
+	BackendMySQL = "mysql" // Full-text search straight on the products table
+	BackendIndex = "index" // In-process InvertedIndex
+)
+
+// Document is the searchable content of a published product
+type Document struct {
+	ProductID   uint
+	CategoryID  uint
+	Title       string
+	Brand       string
+	Description string
+	Attributes  []string // Text values of product and SKU attributes
+	Codes       []string // SKU codes and barcodes, matched exactly
+}
+
+// Query is a search of the index. Filters apply before hits are ranked and
+// cut to Limit, so a narrow filter still finds all of its products.
+type Query struct {
+	Text        string
+	CategoryIDs []uint // Any of these categories, every category when empty
+	Brand       string // Equal ignoring case, every brand when empty
+	Limit       int    // Most hits returned, all when zero
+}
+
+// Hit is a product matching a search, higher scores first
+type Hit struct {
+	ProductID uint
+	Score     float64
+}
+
+// Index is implemented by every search engine the marketplace can use. It
+// only holds published products; visibility, stock and filters are still
+// checked against the database for the hits it returns.
+type Index interface {
+	Upsert(doc Document) error
+	Delete(productID uint) error
+
+	// Search returns the best hits for query and how many products match
+	// it in all, which is more than len(hits) when they were cut to Limit
+	Search(query Query) ([]Hit, int, error)
+
+	// Checkpoint marks the index as reflecting every search event up to
+	// lastEventID. It may persist only now and then; after a restart
+	// LastEvent returns the last ID persisted.
+	Checkpoint(lastEventID uint) error
+	LastEvent() uint
+}
+
+var defaultIndex Index
+
+// Connect opens the index selected by SEARCH_BACKEND. The mysql backend
+// has no index, GetIndex returns nil and services search the database.
+func Connect() {
+	cfg := config.AppConfig
+
+	switch cfg.SearchBackend {
+	case BackendMySQL:
+		return
+	case BackendIndex:
+		var synonyms [][]string
+		if cfg.SearchSynonymsFile != "" {
+			groups, err := LoadSynonyms(cfg.SearchSynonymsFile)
+			if err != nil {
+				log.Fatal("Failed to load search synonyms:", err)
+			}
+			synonyms = groups
+		}
+
+		index, err := OpenInvertedIndex(cfg.SearchIndexPath, NewAnalyzer(synonyms))
+		if err != nil {
+			log.Fatal("Failed to open search index:", err)
+		}
+		SetIndex(index)
+		log.Printf("Search index opened with %d products", index.Len())
+	default:
+		log.Fatalf("Unknown search backend: %s", cfg.SearchBackend)
+	}
+}
+
+// SetIndex replaces the default index, e.g. with a hosted engine
+func SetIndex(index Index) {
+	defaultIndex = index
+}
+
+// GetIndex returns the default index, nil when searching MySQL directly
+func GetIndex() Index {
+	return defaultIndex
+}
+
+// RecordEvent queues a product change for the search index, inside the
+// transaction making the change
+func RecordEvent(tx *gorm.DB, productID uint, eventType string) error {
+	return tx.Create(&models.SearchEvent{ProductID: productID, Type: eventType}).Error
+}
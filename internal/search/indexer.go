@@ -0,0 +1,313 @@
+package search
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+// Event IDs are assigned when a transaction inserts them, not when it
+// commits, so an event can become visible after younger ones. A missing ID
+// is waited for until the event after it is this old; by then its
+// transaction is taken to have rolled back.
+const eventGapTimeout = 10 * time.Minute
+
+const indexBatchSize = 500
+
+// How often applied search events are pruned, and how many are deleted at
+// a time
+const (
+	eventPruneInterval  = time.Hour
+	eventPruneBatchSize = 5000
+)
+
+// Indexer keeps an Index in step with the products table by following the
+// search events recorded by the seller and admin APIs
+type Indexer struct {
+	DB    *gorm.DB
+	Index Index
+
+	// Events past the checkpoint that were already applied, so they are
+	// not applied again while the checkpoint waits for a gap
+	applied map[uint]bool
+}
+
+func NewIndexer(index Index) *Indexer {
+	return &Indexer{
+		DB:      db.GetDB(),
+		Index:   index,
+		applied: make(map[uint]bool),
+	}
+}
+
+// StartIndexer fills the default index on first use, or catches it up
+// from its checkpoint, then applies new events every interval and prunes
+// old ones every eventPruneInterval
+func StartIndexer(interval time.Duration) {
+	index := GetIndex()
+	if index == nil {
+		return
+	}
+	indexer := NewIndexer(index)
+
+	if index.LastEvent() == 0 {
+		if err := indexer.Rebuild(); err != nil {
+			log.Printf("Search index rebuild failed: %v", err)
+		}
+	} else if err := indexer.Sync(); err != nil {
+		log.Printf("Search index sync failed: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		pruneTicker := time.NewTicker(eventPruneInterval)
+		defer pruneTicker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := indexer.Sync(); err != nil {
+					log.Printf("Search index sync failed: %v", err)
+				}
+			case <-pruneTicker.C:
+				if err := indexer.PruneEvents(config.AppConfig.SearchEventRetention); err != nil {
+					log.Printf("Search event pruning failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Rebuild indexes every published product. It checkpoints before the
+// events of the last eventGapTimeout, so the next Sync applies those again
+// along with any made by transactions still open during the rebuild; that
+// is harmless. The checkpoint is never below the oldest event left after
+// pruning, or Sync would take the index to have missed events.
+func (ix *Indexer) Rebuild() error {
+	var lastEvent uint
+	err := ix.DB.Model(&models.SearchEvent{}).
+		Select("COALESCE(MAX(id), 0)").
+		Where("created_at < ?", time.Now().Add(-eventGapTimeout)).
+		Scan(&lastEvent).Error
+	if err != nil {
+		return err
+	}
+	oldest, err := ix.oldestEvent()
+	if err != nil {
+		return err
+	}
+	if oldest > 0 && lastEvent < oldest-1 {
+		lastEvent = oldest - 1
+	}
+
+	var products []models.Product
+	err = ix.DB.
+		Preload("SKUs", "is_active = ?", true).
+		Where("status = ?", models.ProductStatusPublished).
+		FindInBatches(&products, indexBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range products {
+				if err := ix.Index.Upsert(NewDocument(&products[i])); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return ix.Index.Checkpoint(lastEvent)
+}
+
+// Sync applies the search events recorded since the last checkpoint. The
+// checkpoint only moves up to the first gap in event IDs that may still be
+// filled; events after it are applied now and remembered. When events past
+// the checkpoint were already pruned the index is rebuilt instead.
+func (ix *Indexer) Sync() error {
+	checkpoint := ix.Index.LastEvent()
+	oldest, err := ix.oldestEvent()
+	if err != nil {
+		return err
+	}
+	if oldest > checkpoint+1 {
+		log.Printf("Search events after checkpoint %d were pruned; rebuilding the index", checkpoint)
+		clear(ix.applied)
+		return ix.Rebuild()
+	}
+
+	gapDeadline := time.Now().Add(-eventGapTimeout)
+	blocked := false
+
+	for after := checkpoint; ; {
+		var events []models.SearchEvent
+		err := ix.DB.
+			Where("id > ?", after).
+			Order("id ASC").
+			Limit(indexBatchSize).
+			Find(&events).Error
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		if !blocked {
+			var passed int
+			checkpoint, passed = advanceCheckpoint(checkpoint, events, gapDeadline)
+			blocked = passed < len(events)
+		}
+
+		seen := make(map[uint]bool)
+		var productIDs []uint
+		for _, event := range events {
+			if !ix.applied[event.ID] && !seen[event.ProductID] {
+				seen[event.ProductID] = true
+				productIDs = append(productIDs, event.ProductID)
+			}
+		}
+		if err := ix.reindex(productIDs); err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event.ID > checkpoint {
+				ix.applied[event.ID] = true
+			}
+		}
+		if err := ix.Index.Checkpoint(checkpoint); err != nil {
+			return err
+		}
+
+		if len(events) < indexBatchSize {
+			break
+		}
+		after = events[len(events)-1].ID
+	}
+
+	for id := range ix.applied {
+		if id <= checkpoint {
+			delete(ix.applied, id)
+		}
+	}
+	return nil
+}
+
+// PruneEvents deletes search events older than retention, in batches so
+// the table is not locked for long. Every index applies events within
+// seconds; one that was down for longer than retention rebuilds.
+func (ix *Indexer) PruneEvents(retention time.Duration) error {
+	cutoff := time.Now().Add(-max(retention, eventGapTimeout))
+	pruned := int64(0)
+	for {
+		result := ix.DB.Where("created_at < ?", cutoff).Limit(eventPruneBatchSize).Delete(&models.SearchEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		pruned += result.RowsAffected
+		if result.RowsAffected < eventPruneBatchSize {
+			break
+		}
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d search events", pruned)
+	}
+	return nil
+}
+
+// ID of the oldest search event left, 0 when there are none
+func (ix *Indexer) oldestEvent() (uint, error) {
+	var oldest uint
+	err := ix.DB.Model(&models.SearchEvent{}).Select("COALESCE(MIN(id), 0)").Scan(&oldest).Error
+	return oldest, err
+}
+
+// advanceCheckpoint moves the checkpoint over events in ID order for as
+// long as IDs are consecutive, or the event after a gap was recorded
+// before gapDeadline. It returns the new checkpoint and the number of
+// events it passed.
+func advanceCheckpoint(checkpoint uint, events []models.SearchEvent, gapDeadline time.Time) (uint, int) {
+	for i, event := range events {
+		if event.ID != checkpoint+1 && !event.CreatedAt.Before(gapDeadline) {
+			return checkpoint, i
+		}
+		checkpoint = event.ID
+	}
+	return checkpoint, len(events)
+}
+
+// Index the current state of the given products: events only say which
+// products changed, so replaying them in any order ends up the same
+func (ix *Indexer) reindex(productIDs []uint) error {
+	var products []models.Product
+	err := ix.DB.
+		Preload("SKUs", "is_active = ?", true).
+		Where("id IN ? AND status = ?", productIDs, models.ProductStatusPublished).
+		Find(&products).Error
+	if err != nil {
+		return err
+	}
+
+	published := make(map[uint]bool, len(products))
+	for i := range products {
+		published[products[i].ID] = true
+		if err := ix.Index.Upsert(NewDocument(&products[i])); err != nil {
+			return err
+		}
+	}
+	for _, productID := range productIDs {
+		if published[productID] {
+			continue
+		}
+		if err := ix.Index.Delete(productID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewDocument builds the search document of a product loaded with its
+// active SKUs. Only text attribute values are searchable.
+func NewDocument(product *models.Product) Document {
+	doc := Document{
+		ProductID:   product.ID,
+		CategoryID:  product.CategoryID,
+		Title:       product.Title,
+		Brand:       product.Brand,
+		Description: product.Description,
+	}
+
+	values := make(map[string]bool)
+	addAttributes := func(attrs models.Attributes) {
+		for _, value := range attrs {
+			if text, ok := value.(string); ok && text != "" {
+				values[text] = true
+			}
+		}
+	}
+	if attrs, err := product.GetAttributes(); err == nil {
+		addAttributes(attrs)
+	}
+	for i := range product.SKUs {
+		sku := &product.SKUs[i]
+		if attrs, err := sku.GetAttributes(); err == nil {
+			addAttributes(attrs)
+		}
+		doc.Codes = append(doc.Codes, sku.SKUCode)
+		if sku.Barcode != "" {
+			doc.Codes = append(doc.Codes, sku.Barcode)
+		}
+	}
+	for value := range values {
+		doc.Attributes = append(doc.Attributes, value)
+	}
+	sort.Strings(doc.Attributes)
+
+	return doc
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"gocom/main/internal/models"
+)
+
+func TestAdvanceCheckpoint(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(-eventGapTimeout)
+	fresh := now.Add(-time.Second)
+	stale := deadline.Add(-time.Minute)
+
+	event := func(id uint, createdAt time.Time) models.SearchEvent {
+		return models.SearchEvent{ID: id, CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name       string
+		checkpoint uint
+		events     []models.SearchEvent
+		want       uint
+		wantPassed int
+	}{
+		{
+			name:       "consecutive",
+			checkpoint: 10,
+			events:     []models.SearchEvent{event(11, fresh), event(12, fresh)},
+			want:       12,
+			wantPassed: 2,
+		},
+		{
+			name:       "gap still open",
+			checkpoint: 10,
+			events:     []models.SearchEvent{event(11, fresh), event(13, fresh), event(14, fresh)},
+			want:       11,
+			wantPassed: 1,
+		},
+		{
+			name:       "gap at the start",
+			checkpoint: 10,
+			events:     []models.SearchEvent{event(12, fresh)},
+			want:       10,
+			wantPassed: 0,
+		},
+		{
+			name:       "gap timed out",
+			checkpoint: 10,
+			events:     []models.SearchEvent{event(12, stale), event(13, fresh), event(15, fresh)},
+			want:       13,
+			wantPassed: 2,
+		},
+	}
+	for _, tt := range tests {
+		got, passed := advanceCheckpoint(tt.checkpoint, tt.events, deadline)
+		if got != tt.want || passed != tt.wantPassed {
+			t.Errorf("%s: advanceCheckpoint = %d, %d; want %d, %d", tt.name, got, passed, tt.want, tt.wantPassed)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gocom/main/internal/common/validation"
+)
+
+// Field weights, a term in the title says more about a product than one
+// in its description
+const (
+	weightTitle       = 3.0
+	weightBrand       = 2.5
+	weightAttribute   = 1.5
+	weightDescription = 1.0
+)
+
+// How much a query term counts when it matches through a synonym, as the
+// prefix of the last word, or only with a typo
+const (
+	qualitySynonym = 0.9
+	qualityPrefix  = 0.7
+	qualityTypo    = 0.6
+)
+
+// Words shorter than this are not typo-corrected, there are too many
+// one-letter neighbours
+const minTypoLength = 4
+
+// Checkpoints write a snapshot at most this often. Events after the last
+// snapshot are applied again after a restart, which is harmless.
+const snapshotInterval = time.Minute
+
+// InvertedIndex is an Index kept in memory and, when it has a path,
+// snapshotted to disk at checkpoints. It matches products containing
+// every query term, allowing synonyms, a prefix for the last term and a
+// typo or two in terms the index does not know, and ranks them by
+// field-weighted term frequency and rarity.
+type InvertedIndex struct {
+	path     string
+	analyzer *Analyzer
+
+	mu        sync.RWMutex
+	docs      map[uint]Document
+	postings  map[string]map[uint]float64 // Term to product to weighted frequency
+	initials  map[rune]map[string]bool    // Vocabulary by first letter
+	codes     map[string]map[uint]bool
+	lastEvent uint
+	saved     bool
+
+	// Held while a snapshot is written, outside mu so searches and
+	// updates carry on
+	snapshotMu       sync.Mutex
+	snapshotInterval time.Duration
+	snapshotAt       time.Time
+}
+
+// Version of the snapshot layout; a snapshot of another version is not
+// loaded, leaving the index empty so it is rebuilt
+const snapshotVersion = 2
+
+// indexSnapshot is the on-disk form of an InvertedIndex. Documents are
+// stored rather than postings so analyzer changes apply on the next load.
+type indexSnapshot struct {
+	Version   int
+	LastEvent uint
+	Docs      []Document
+}
+
+// OpenInvertedIndex loads the index snapshot at path, starting empty when
+// there is none yet or it cannot be read back, so the index is rebuilt.
+// An empty path keeps the index in memory only.
+func OpenInvertedIndex(path string, analyzer *Analyzer) (*InvertedIndex, error) {
+	ix := &InvertedIndex{
+		path:             path,
+		analyzer:         analyzer,
+		docs:             make(map[uint]Document),
+		postings:         make(map[string]map[uint]float64),
+		initials:         make(map[rune]map[string]bool),
+		codes:            make(map[string]map[uint]bool),
+		snapshotInterval: snapshotInterval,
+	}
+	if path == "" {
+		return ix, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snapshot indexSnapshot
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		log.Printf("Search index snapshot %s is unreadable, rebuilding: %v", path, err)
+		return ix, nil
+	}
+	if snapshot.Version != snapshotVersion {
+		log.Printf("Search index snapshot is version %d, expected %d; rebuilding", snapshot.Version, snapshotVersion)
+		return ix, nil
+	}
+	for _, doc := range snapshot.Docs {
+		ix.add(doc)
+	}
+	ix.lastEvent = snapshot.LastEvent
+	ix.saved = true
+	return ix, nil
+}
+
+// Len returns the number of indexed products
+func (ix *InvertedIndex) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+func (ix *InvertedIndex) Upsert(doc Document) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(doc.ProductID)
+	ix.add(doc)
+	ix.saved = false
+	return nil
+}
+
+func (ix *InvertedIndex) Delete(productID uint) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if _, ok := ix.docs[productID]; ok {
+		ix.remove(productID)
+		ix.saved = false
+	}
+	return nil
+}
+
+func (ix *InvertedIndex) LastEvent() uint {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.lastEvent
+}
+
+// Checkpoint records lastEventID and writes a snapshot when the last one
+// is older than the snapshot interval. The documents are copied under the
+// lock and encoded after it is released; they are replaced on update, never
+// changed in place, so the copies stay valid.
+func (ix *InvertedIndex) Checkpoint(lastEventID uint) error {
+	ix.snapshotMu.Lock()
+	defer ix.snapshotMu.Unlock()
+
+	ix.mu.Lock()
+	if ix.lastEvent != lastEventID {
+		ix.lastEvent = lastEventID
+		ix.saved = false
+	}
+	if ix.path == "" || ix.saved || time.Since(ix.snapshotAt) < ix.snapshotInterval {
+		ix.mu.Unlock()
+		return nil
+	}
+
+	snapshot := indexSnapshot{Version: snapshotVersion, LastEvent: ix.lastEvent, Docs: make([]Document, 0, len(ix.docs))}
+	for _, doc := range ix.docs {
+		snapshot.Docs = append(snapshot.Docs, doc)
+	}
+	// Changes made while the snapshot is written mark it unsaved again
+	ix.saved = true
+	ix.snapshotAt = time.Now()
+	ix.mu.Unlock()
+
+	if err := ix.writeSnapshot(&snapshot); err != nil {
+		ix.mu.Lock()
+		ix.saved = false
+		ix.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// writeSnapshot replaces the snapshot through a temporary file, so a crash
+// leaves the previous snapshot in place
+func (ix *InvertedIndex) writeSnapshot(snapshot *indexSnapshot) error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(ix.path), filepath.Base(ix.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := gob.NewEncoder(file).Encode(snapshot); err != nil {
+		file.Close()
+		return err
+	}
+	// Make the data durable before the rename, or a crash could leave an
+	// empty snapshot in place of the old one
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), ix.path)
+}
+
+// Search returns the products matching every term of the text, plus
+// products with a SKU code or barcode equal to the whole text, which rank
+// first
+func (ix *InvertedIndex) Search(query Query) ([]Hit, int, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	scores := ix.matchTerms(query.Text)
+	for productID := range ix.matchCodes(query.Text) {
+		if scores == nil {
+			scores = make(map[uint]float64)
+		}
+		scores[productID] += exactMatchBoost
+	}
+
+	var categories map[uint]bool
+	if len(query.CategoryIDs) > 0 {
+		categories = make(map[uint]bool, len(query.CategoryIDs))
+		for _, id := range query.CategoryIDs {
+			categories[id] = true
+		}
+	}
+	hits := make([]Hit, 0, len(scores))
+	for productID, score := range scores {
+		doc := ix.docs[productID]
+		if categories != nil && !categories[doc.CategoryID] {
+			continue
+		}
+		if query.Brand != "" && !strings.EqualFold(doc.Brand, query.Brand) {
+			continue
+		}
+		hits = append(hits, Hit{ProductID: productID, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ProductID > hits[j].ProductID
+	})
+
+	total := len(hits)
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, total, nil
+}
+
+// Score the products containing every query term
+func (ix *InvertedIndex) matchTerms(text string) map[uint]float64 {
+	terms := ix.analyzer.Terms(text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var scores map[uint]float64
+	for i, term := range terms {
+		termScores := make(map[uint]float64)
+		for expansion, quality := range ix.expand(term, i == len(terms)-1) {
+			postings := ix.postings[expansion]
+			idf := math.Log(1 + float64(len(ix.docs))/float64(len(postings)))
+			for productID, frequency := range postings {
+				score := quality * idf * (1 + math.Log(frequency))
+				if score > termScores[productID] {
+					termScores[productID] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for productID, score := range scores {
+			if termScore, ok := termScores[productID]; ok {
+				scores[productID] = score + termScore
+			} else {
+				delete(scores, productID)
+			}
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+	return scores
+}
+
+// Indexed terms a query term matches, with the quality of each match.
+// Typos are only corrected when neither the term nor a synonym is known,
+// so correctly spelled words never pull in lookalikes.
+func (ix *InvertedIndex) expand(term string, last bool) map[string]float64 {
+	expansions := make(map[string]float64)
+	if ix.postings[term] != nil {
+		expansions[term] = 1
+	}
+	for _, synonym := range ix.analyzer.Synonyms(term) {
+		if ix.postings[synonym] != nil {
+			expansions[synonym] = qualitySynonym
+		}
+	}
+
+	initial, _ := utf8.DecodeRuneInString(term)
+	length := utf8.RuneCountInString(term)
+	if last {
+		for candidate := range ix.initials[initial] {
+			if candidate != term && strings.HasPrefix(candidate, term) {
+				expansions[candidate] = math.Max(expansions[candidate], qualityPrefix)
+			}
+		}
+	}
+	if len(expansions) == 0 && length >= minTypoLength {
+		maxDistance := 1
+		if length >= 8 {
+			maxDistance = 2
+		}
+		for candidate := range ix.initials[initial] {
+			if withinDistance(term, candidate, maxDistance) {
+				expansions[candidate] = qualityTypo
+			}
+		}
+	}
+	return expansions
+}
+
+// Products with a SKU code or barcode equal to text
+func (ix *InvertedIndex) matchCodes(text string) map[uint]bool {
+	matches := make(map[uint]bool)
+	for productID := range ix.codes[normalizeCode(text)] {
+		matches[productID] = true
+	}
+	if barcode := validation.NormalizeBarcode(text); validation.ValidateBarcode(barcode) == nil {
+		for _, variant := range validation.BarcodeVariants(barcode) {
+			for productID := range ix.codes[normalizeCode(variant)] {
+				matches[productID] = true
+			}
+		}
+	}
+	return matches
+}
+
+// add indexes a document; the caller holds the write lock
+func (ix *InvertedIndex) add(doc Document) {
+	ix.docs[doc.ProductID] = doc
+	for term, frequency := range ix.documentTerms(doc) {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[uint]float64)
+			initial, _ := utf8.DecodeRuneInString(term)
+			if ix.initials[initial] == nil {
+				ix.initials[initial] = make(map[string]bool)
+			}
+			ix.initials[initial][term] = true
+		}
+		ix.postings[term][doc.ProductID] = frequency
+	}
+	for _, code := range doc.Codes {
+		code = normalizeCode(code)
+		if code == "" {
+			continue
+		}
+		if ix.codes[code] == nil {
+			ix.codes[code] = make(map[uint]bool)
+		}
+		ix.codes[code][doc.ProductID] = true
+	}
+}
+
+// remove drops a document from the index; the caller holds the write lock
+func (ix *InvertedIndex) remove(productID uint) {
+	doc, ok := ix.docs[productID]
+	if !ok {
+		return
+	}
+	delete(ix.docs, productID)
+
+	for term := range ix.documentTerms(doc) {
+		delete(ix.postings[term], productID)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+			initial, _ := utf8.DecodeRuneInString(term)
+			delete(ix.initials[initial], term)
+		}
+	}
+	for _, code := range doc.Codes {
+		code = normalizeCode(code)
+		delete(ix.codes[code], productID)
+		if len(ix.codes[code]) == 0 {
+			delete(ix.codes, code)
+		}
+	}
+}
+
+// Weighted frequency of every term of a document
+func (ix *InvertedIndex) documentTerms(doc Document) map[string]float64 {
+	terms := make(map[string]float64)
+	addField := func(text string, weight float64) {
+		for _, term := range ix.analyzer.Terms(text) {
+			terms[term] += weight
+		}
+	}
+
+	addField(doc.Title, weightTitle)
+	addField(doc.Brand, weightBrand)
+	for _, value := range doc.Attributes {
+		addField(value, weightAttribute)
+	}
+	addField(doc.Description, weightDescription)
+	return terms
+}
+
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// withinDistance reports whether the Levenshtein distance between a and b
+// is at most max, giving up on a row as soon as it cannot get there
+func withinDistance(a, b string, max int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return false
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			best = min(best, curr[j])
+		}
+		if best > max {
+			return false
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)] <= max
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,240 @@
+package search
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithinDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want bool
+	}{
+		{"shirt", "shirt", 0, true},
+		{"shirt", "shrit", 1, false}, // A transposition is two edits
+		{"shirt", "shrit", 2, true},
+		{"shirt", "shirts", 1, true},
+		{"shirt", "shir", 1, true},
+		{"shirt", "short", 1, true},
+		{"shirt", "shorts", 1, false},
+		{"headphone", "headphnoe", 2, true},
+		{"kettle", "kettlebell", 2, false},
+		{"café", "cafe", 1, true},
+	}
+	for _, tt := range tests {
+		if got := withinDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("withinDistance(%q, %q, %d) = %v, want %v", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
+
+func newTestIndex(t *testing.T, path string) *InvertedIndex {
+	t.Helper()
+	ix, err := OpenInvertedIndex(path, NewAnalyzer([][]string{{"sofa", "couch"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := []Document{
+		{ProductID: 1, CategoryID: 10, Title: "Cotton shirt", Brand: "Acme", Codes: []string{"ACME-SH-1"}},
+		{ProductID: 2, CategoryID: 10, Title: "Linen shirts", Brand: "Other", Description: "A shirt for summer"},
+		{ProductID: 3, CategoryID: 20, Title: "Leather sofa", Brand: "Acme"},
+		{ProductID: 4, CategoryID: 30, Title: "Electric kettle", Brand: "Boil", Codes: []string{"0036000291452"}},
+	}
+	for _, doc := range docs {
+		if err := ix.Upsert(doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ix
+}
+
+func hitIDs(hits []Hit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ProductID
+	}
+	return ids
+}
+
+func TestInvertedIndexSearch(t *testing.T) {
+	ix := newTestIndex(t, "")
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []uint
+	}{
+		{"stemmed", Query{Text: "shirt"}, []uint{2, 1}},
+		{"every term", Query{Text: "cotton shirts"}, []uint{1}},
+		{"synonym", Query{Text: "couch"}, []uint{3}},
+		{"prefix of last term", Query{Text: "kett"}, []uint{4}},
+		{"typo", Query{Text: "ketle"}, []uint{4}},
+		{"code", Query{Text: "acme-sh-1"}, []uint{1}},
+		{"UPC-A of an EAN-13", Query{Text: "036000291452"}, []uint{4}},
+		{"category", Query{Text: "shirt", CategoryIDs: []uint{10, 20}}, []uint{2, 1}},
+		{"other category", Query{Text: "shirt", CategoryIDs: []uint{20}}, []uint{}},
+		{"brand", Query{Text: "shirt", Brand: "acme"}, []uint{1}},
+		{"no match", Query{Text: "umbrella"}, []uint{}},
+	}
+	for _, tt := range tests {
+		hits, total, err := ix.Search(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hitIDs(hits); !equalIDs(got, tt.want) || total != len(tt.want) {
+			t.Errorf("%s: Search(%+v) = %v (total %d), want %v", tt.name, tt.query, got, total, tt.want)
+		}
+	}
+}
+
+func TestInvertedIndexSearchLimit(t *testing.T) {
+	ix := newTestIndex(t, "")
+
+	hits, total, err := ix.Search(Query{Text: "shirt", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || total != 2 {
+		t.Errorf("Search with limit 1 = %d hits (total %d), want 1 hit (total 2)", len(hits), total)
+	}
+}
+
+func TestInvertedIndexDelete(t *testing.T) {
+	ix := newTestIndex(t, "")
+	if err := ix.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+
+	hits, _, err := ix.Search(Query{Text: "cotton"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("Search after delete = %v, want none", hitIDs(hits))
+	}
+	if ix.Len() != 3 {
+		t.Errorf("Len = %d, want 3", ix.Len())
+	}
+}
+
+func TestInvertedIndexCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "products.gob")
+	ix := newTestIndex(t, path)
+	if err := ix.Checkpoint(42); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenInvertedIndex(path, NewAnalyzer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.LastEvent() != 42 || reopened.Len() != 4 {
+		t.Fatalf("reopened index has event %d and %d products, want 42 and 4", reopened.LastEvent(), reopened.Len())
+	}
+	hits, _, err := reopened.Search(Query{Text: "shirt", CategoryIDs: []uint{10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hitIDs(hits); !equalIDs(got, []uint{2, 1}) {
+		t.Errorf("Search after reopening = %v, want [2 1]", got)
+	}
+
+	// No temporary files are left next to the snapshot
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("index directory has %d entries, want only the snapshot", len(entries))
+	}
+}
+
+func TestInvertedIndexCheckpointInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.gob")
+	ix := newTestIndex(t, path)
+	if err := ix.Checkpoint(42); err != nil {
+		t.Fatal(err)
+	}
+
+	// Within the interval the checkpoint is only kept in memory
+	if err := ix.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Checkpoint(43); err != nil {
+		t.Fatal(err)
+	}
+	if ix.LastEvent() != 43 {
+		t.Errorf("LastEvent = %d, want 43", ix.LastEvent())
+	}
+	reopened, err := OpenInvertedIndex(path, NewAnalyzer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.LastEvent() != 42 || reopened.Len() != 4 {
+		t.Errorf("snapshot within the interval has event %d and %d products, want 42 and 4", reopened.LastEvent(), reopened.Len())
+	}
+
+	ix.snapshotAt = time.Now().Add(-ix.snapshotInterval)
+	if err := ix.Checkpoint(43); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err = OpenInvertedIndex(path, NewAnalyzer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.LastEvent() != 43 || reopened.Len() != 3 {
+		t.Errorf("snapshot after the interval has event %d and %d products, want 43 and 3", reopened.LastEvent(), reopened.Len())
+	}
+}
+
+func TestInvertedIndexOldSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.gob")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := indexSnapshot{LastEvent: 7, Docs: []Document{{ProductID: 1, Title: "Cotton shirt"}}}
+	if err := gob.NewEncoder(file).Encode(&old); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	ix, err := OpenInvertedIndex(path, NewAnalyzer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ix.LastEvent() != 0 || ix.Len() != 0 {
+		t.Errorf("index from an old snapshot has event %d and %d products, want an empty index", ix.LastEvent(), ix.Len())
+	}
+}
+
+func TestInvertedIndexCorruptSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.gob")
+	if err := os.WriteFile(path, []byte("not a snapshot"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ix, err := OpenInvertedIndex(path, NewAnalyzer(nil))
+	if err != nil {
+		t.Fatalf("OpenInvertedIndex with a corrupt snapshot = %v, want an empty index", err)
+	}
+	if ix.LastEvent() != 0 || ix.Len() != 0 {
+		t.Errorf("index from a corrupt snapshot has event %d and %d products, want an empty index", ix.LastEvent(), ix.Len())
+	}
+}
+
+func equalIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
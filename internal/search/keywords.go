@@ -0,0 +1,56 @@
+package search
+
+import "strings"
+
+// KeywordMatcher finds keywords and phrases in text as whole words,
+// ignoring case and punctuation. Product moderation and search suggestions
+// share it so that a prohibited keyword means the same in both.
+type KeywordMatcher struct {
+	keywords []string
+	phrases  []string // Tokenized keywords, padded with spaces
+}
+
+// NewKeywordMatcher tokenizes every keyword once. Keywords without a
+// letter or digit never match.
+func NewKeywordMatcher(keywords []string) *KeywordMatcher {
+	km := &KeywordMatcher{}
+	for _, keyword := range keywords {
+		if phrase := NormalizeKeyword(keyword); phrase != "" {
+			km.keywords = append(km.keywords, keyword)
+			km.phrases = append(km.phrases, " "+phrase+" ")
+		}
+	}
+	return km
+}
+
+// NormalizeKeyword returns the words of a keyword joined by single spaces,
+// empty when it has none
+func NormalizeKeyword(keyword string) string {
+	return strings.Join(Tokenize(keyword), " ")
+}
+
+// Match returns the keywords found in text, in the order they were given
+func (km *KeywordMatcher) Match(text string) []string {
+	var found []string
+	if len(km.phrases) == 0 {
+		return found
+	}
+	padded := " " + NormalizeKeyword(text) + " "
+	for i, phrase := range km.phrases {
+		if strings.Contains(padded, phrase) {
+			found = append(found, km.keywords[i])
+		}
+	}
+	return found
+}
+
+// Contains reports whether text has any of the keywords
+func (km *KeywordMatcher) Contains(text string) bool {
+	padded := " " + NormalizeKeyword(text) + " "
+	for _, phrase := range km.phrases {
+		if strings.Contains(padded, phrase) {
+			return true
+		}
+	}
+	return false
+}
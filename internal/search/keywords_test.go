@@ -0,0 +1,45 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywordMatcher(t *testing.T) {
+	km := NewKeywordMatcher([]string{"replica", "Fake  Rolex", "---", ""})
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Luxury REPLICA watch", []string{"replica"}},
+		{"replicas of classics", nil},
+		{"fake-rolex, boxed", []string{"Fake  Rolex"}},
+		{"fake watch by rolex", nil},
+		{"Replica fake rolex", []string{"replica", "Fake  Rolex"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := km.Match(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Match(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if km.Contains(tt.text) != (len(tt.want) > 0) {
+			t.Errorf("Contains(%q) = %v, want %v", tt.text, !(len(tt.want) > 0), len(tt.want) > 0)
+		}
+	}
+}
+
+func TestNormalizeKeyword(t *testing.T) {
+	tests := map[string]string{
+		"  Fake   Rolex ": "fake rolex",
+		"fake-rolex":      "fake rolex",
+		"---":             "",
+		"":                "",
+	}
+	for keyword, want := range tests {
+		if got := NormalizeKeyword(keyword); got != want {
+			t.Errorf("NormalizeKeyword(%q) = %q, want %q", keyword, got, want)
+		}
+	}
+}
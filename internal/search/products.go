@@ -0,0 +1,144 @@
+// Package search implements product search: full-text search on MySQL,
+// shared by the seller and marketplace APIs, and the Index the marketplace
+// searches when it runs on a dedicated search engine
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/validation"
+)
+
+// matchColumns must list exactly the columns of the products FULLTEXT
+// index, otherwise MySQL refuses the MATCH
+const matchColumns = "products.title, products.description, products.brand"
+
+// InnoDB does not index words shorter than innodb_ft_min_token_size, so
+// requiring them would make every search for them come back empty
+const minRequiredTermLength = 3
+
+// An exact SKU code or barcode hit ranks above any text relevance
+const exactMatchBoost = 1000
+
+// ProductSearch is a parsed buyer or seller search. It matches products
+// whose title, description or brand contain every term, or which have a
+// SKU whose code or barcode equals the whole search text.
+type ProductSearch struct {
+	Text string
+
+	// ActiveSKUsOnly restricts code and barcode matches to active SKUs,
+	// for buyer-facing searches
+	ActiveSKUsOnly bool
+
+	against  string
+	codes    []string
+	barcodes []string
+}
+
+// NewProductSearch parses text, returning nil when there is nothing to
+// search for
+func NewProductSearch(text string) *ProductSearch {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	s := &ProductSearch{Text: text, codes: []string{text}}
+	s.against = booleanQuery(text)
+	if barcode := validation.NormalizeBarcode(text); validation.ValidateBarcode(barcode) == nil {
+		s.barcodes = validation.BarcodeVariants(barcode)
+	}
+	return s
+}
+
+// Filter narrows a products query to the matching products
+func (s *ProductSearch) Filter(query *gorm.DB) *gorm.DB {
+	skuMatch, skuVars := s.skuCondition()
+	if s.against == "" {
+		return query.Where("products.id IN ("+skuMatch+")", skuVars...)
+	}
+
+	vars := append([]interface{}{s.against}, skuVars...)
+	return query.Where("(MATCH("+matchColumns+") AGAINST(? IN BOOLEAN MODE) OR products.id IN ("+skuMatch+"))", vars...)
+}
+
+// OrderByRelevance orders a filtered query by exact SKU hits first, then
+// full-text relevance. The score is selected as a column because GORM
+// drops an ORDER BY expression once further orders are added.
+func (s *ProductSearch) OrderByRelevance(query *gorm.DB) *gorm.DB {
+	relevance, vars := s.Relevance()
+	return query.
+		Select("products.*, ("+relevance+") AS search_relevance", vars...).
+		Order("search_relevance DESC")
+}
+
+// Relevance returns the SQL expression scoring a product row against the
+// search, for queries that select their own columns
+func (s *ProductSearch) Relevance() (string, []interface{}) {
+	skuMatch, skuVars := s.skuCondition()
+	sql := "CASE WHEN products.id IN (" + skuMatch + ") THEN ? ELSE 0 END"
+	vars := append(skuVars, exactMatchBoost)
+	if s.against != "" {
+		sql += " + MATCH(" + matchColumns + ") AGAINST(? IN BOOLEAN MODE)"
+		vars = append(vars, s.against)
+	}
+	return sql, vars
+}
+
+func (s *ProductSearch) skuCondition() (string, []interface{}) {
+	sql := "SELECT skus.product_id FROM skus WHERE (skus.sku_code IN ?"
+	vars := []interface{}{s.codes}
+	if len(s.barcodes) > 0 {
+		sql += " OR skus.barcode IN ?"
+		vars = append(vars, s.barcodes)
+	}
+	sql += ")"
+	if s.ActiveSKUsOnly {
+		sql += " AND skus.is_active = ?"
+		vars = append(vars, true)
+	}
+	return sql, vars
+}
+
+// booleanQuery turns free text into a BOOLEAN MODE expression: every term
+// long enough to be indexed is required, and the last one also matches as
+// a prefix so results keep up while the user is typing
+func booleanQuery(text string) string {
+	terms := Tokenize(text)
+	parts := make([]string, 0, len(terms))
+	for i, term := range terms {
+		part := term
+		if i == len(terms)-1 {
+			part += "*"
+		}
+		if len([]rune(term)) >= minRequiredTermLength {
+			part = "+" + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Tokenize splits text into lowercase words. Anything other than letters
+// and digits separates words, which also strips the BOOLEAN MODE operators.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormalizeQuery lowercases text and collapses whitespace, so logged
+// searches that differ only in case or spacing count as one
+func NormalizeQuery(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// LikePrefix returns a LIKE pattern matching values starting with text,
+// with LIKE wildcards in text escaped
+func LikePrefix(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(text) + "%"
+}
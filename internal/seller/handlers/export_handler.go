@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+type ExportHandler struct {
+	ExportService *services.ExportService
+}
+
+func NewExportHandler() *ExportHandler {
+	return &ExportHandler{
+		ExportService: services.NewExportService(),
+	}
+}
+
+// Queue a catalog export
+// POST /v1/sellers/:id/products/export
+func (eh *ExportHandler) CreateExport(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+	principal, _ := auth.GetPrincipal(c)
+
+	var req services.CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := eh.ExportService.CreateExport(sellerID, principal.UserID, &req)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    job,
+		"message": "Export queued",
+	})
+}
+
+// List export jobs
+// GET /v1/sellers/:id/exports
+func (eh *ExportHandler) ListExports(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	var filters services.ExportFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobs, total, err := eh.ExportService.ListExports(sellerID, filters.Page, filters.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"exports": jobs,
+			"total":   total,
+			"page":    filters.Page,
+			"limit":   filters.Limit,
+		},
+	})
+}
+
+// Get export job status and download link
+// GET /v1/sellers/:id/exports/:jobId
+func (eh *ExportHandler) GetExport(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	sellerID := middleware.GetSellerID(c)
+
+	job, err := eh.ExportService.GetExport(sellerID, uint(jobID))
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    job,
+	})
+}
+
+func respondExportError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrExportFormat), stderrors.Is(err, services.ErrExportStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
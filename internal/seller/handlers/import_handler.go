@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/common/spreadsheet"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+type ImportHandler struct {
+	ImportService *services.ImportService
+}
+
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		ImportService: services.NewImportService(),
+	}
+}
+
+// Upload a CSV or XLSX catalog file for asynchronous import
+// POST /v1/sellers/:id/products/import
+func (ih *ImportHandler) CreateImport(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+	principal, _ := auth.GetPrincipal(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.ImportMaxBytes+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required and must be within the size limit"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	job, err := ih.ImportService.CreateImport(sellerID, principal.UserID, header.Filename, data)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    job,
+		"message": "Import queued",
+	})
+}
+
+// List import jobs
+// GET /v1/sellers/:id/imports
+func (ih *ImportHandler) ListImports(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	var filters services.ImportFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobs, total, err := ih.ImportService.ListImports(sellerID, filters.Page, filters.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"imports": jobs,
+			"total":   total,
+			"page":    filters.Page,
+			"limit":   filters.Limit,
+		},
+	})
+}
+
+// Get import job status, progress and error report link
+// GET /v1/sellers/:id/imports/:jobId
+func (ih *ImportHandler) GetImport(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	sellerID := middleware.GetSellerID(c)
+
+	job, err := ih.ImportService.GetImport(sellerID, uint(jobID))
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    job,
+	})
+}
+
+func respondImportError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrImportNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrImportTooLarge), stderrors.Is(err, spreadsheet.ErrXLSXTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrImportEmpty), stderrors.Is(err, services.ErrImportTooManyRows),
+		stderrors.Is(err, services.ErrImportColumns), stderrors.Is(err, spreadsheet.ErrUnsupportedFormat),
+		stderrors.Is(err, spreadsheet.ErrInvalidXLSX):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+// Room for multipart boundaries and form fields on top of the file itself
+const multipartOverhead = 1 << 20
+
+type KYCHandler struct {
+	KYCService *services.KYCService
+}
+
+func NewKYCHandler() *KYCHandler {
+	return &KYCHandler{
+		KYCService: services.NewKYCService(),
+	}
+}
+
+// Upload KYC document
+// POST /v1/sellers/:id/kyc
+func (kh *KYCHandler) UploadDocument(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.KYCMaxUploadBytes+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required and must be within the size limit"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	defer file.Close()
+
+	document, err := kh.KYCService.UploadDocument(sellerID, c.PostForm("type"), file, header.Size)
+	if err != nil {
+		respondKYCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    document,
+		"message": "Document uploaded, pending review",
+	})
+}
+
+// List KYC documents
+// GET /v1/sellers/:id/kyc
+func (kh *KYCHandler) ListDocuments(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	documents, err := kh.KYCService.ListDocuments(sellerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    documents,
+	})
+}
+
+func respondKYCError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrKYCTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrKYCContentType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrInvalidKYCType), stderrors.Is(err, services.ErrKYCEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
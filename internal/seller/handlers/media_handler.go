@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/common/imaging"
+	"gocom/main/internal/seller/services"
+)
+
+type MediaHandler struct {
+	MediaService *services.MediaService
+}
+
+func NewMediaHandler() *MediaHandler {
+	return &MediaHandler{
+		MediaService: services.NewMediaService(),
+	}
+}
+
+// List product media
+// GET /v1/products/:id/media
+func (mh *MediaHandler) ListMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	media, err := mh.MediaService.ListMedia(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    media,
+	})
+}
+
+// Upload product media
+// POST /v1/products/:id/media
+func (mh *MediaHandler) UploadMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MediaMaxVideoBytes+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required and must be within the size limit"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	defer file.Close()
+
+	media, err := mh.MediaService.UploadMedia(uint(productID), file, header.Size, c.PostForm("alt_text"))
+	if err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    media,
+		"message": "Media uploaded successfully",
+	})
+}
+
+// Update media alt text
+// PATCH /v1/products/:id/media/:mediaId
+func (mh *MediaHandler) UpdateMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.UpdateMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := mh.MediaService.UpdateAltText(uint(productID), uint(mediaID), req.AltText)
+	if err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    media,
+	})
+}
+
+// Reorder product media
+// PUT /v1/products/:id/media/order
+func (mh *MediaHandler) ReorderMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.ReorderMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := mh.MediaService.ReorderMedia(uint(productID), req.MediaIDs)
+	if err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    media,
+	})
+}
+
+// Delete product media
+// DELETE /v1/products/:id/media/:mediaId
+func (mh *MediaHandler) DeleteMedia(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+	mediaID, err := strconv.ParseUint(c.Param("mediaId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	if err := mh.MediaService.DeleteMedia(uint(productID), uint(mediaID)); err != nil {
+		respondMediaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Media deleted",
+	})
+}
+
+func respondMediaError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrMediaTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrMediaContentType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrMediaEmpty), stderrors.Is(err, services.ErrMediaOrderInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case stderrors.Is(err, imaging.ErrNotAnImage), stderrors.Is(err, imaging.ErrTooSmall),
+		stderrors.Is(err, imaging.ErrTooManyPixels), stderrors.Is(err, imaging.ErrAspectRatio):
+		c.JSON(http.StatusUnprocessableEntity, errors.NewAPIError(http.StatusUnprocessableEntity, errors.ErrValidation.Message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+type MemberHandler struct {
+	MemberService *services.MemberService
+}
+
+func NewMemberHandler() *MemberHandler {
+	return &MemberHandler{
+		MemberService: services.NewMemberService(),
+	}
+}
+
+// List team members
+// GET /v1/sellers/:id/members
+func (mh *MemberHandler) ListMembers(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	members, err := mh.MemberService.ListMembers(sellerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    members,
+	})
+}
+
+// Invite team member
+// POST /v1/sellers/:id/members
+func (mh *MemberHandler) InviteMember(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	var req services.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := mh.MemberService.InviteMember(sellerID, &req)
+	if err != nil {
+		respondMemberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    member,
+		"message": "Member invited successfully",
+	})
+}
+
+// Accept invitation
+// POST /v1/sellers/:id/invitation/accept
+func (mh *MemberHandler) AcceptInvitation(c *gin.Context) {
+	sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	member, err := mh.MemberService.AcceptInvitation(uint(sellerID), principal.UserID)
+	if err != nil {
+		respondMemberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    member,
+		"message": "Invitation accepted",
+	})
+}
+
+// Change team member role
+// PATCH /v1/sellers/:id/members/:memberId
+func (mh *MemberHandler) ChangeRole(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	memberID, err := strconv.ParseUint(c.Param("memberId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := mh.MemberService.ChangeRole(sellerID, uint(memberID), req.Role)
+	if err != nil {
+		respondMemberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    member,
+		"message": "Member role updated",
+	})
+}
+
+// Suspend team member
+// POST /v1/sellers/:id/members/:memberId/suspend
+func (mh *MemberHandler) SuspendMember(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	memberID, err := strconv.ParseUint(c.Param("memberId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	member, err := mh.MemberService.SuspendMember(sellerID, uint(memberID))
+	if err != nil {
+		respondMemberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    member,
+		"message": "Member suspended",
+	})
+}
+
+// Remove team member
+// DELETE /v1/sellers/:id/members/:memberId
+func (mh *MemberHandler) RemoveMember(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	memberID, err := strconv.ParseUint(c.Param("memberId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	if err := mh.MemberService.RemoveMember(sellerID, uint(memberID)); err != nil {
+		respondMemberError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Member removed",
+	})
+}
+
+func respondMemberError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrMemberNotFound), stderrors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrInvalidRole), stderrors.Is(err, services.ErrLastOwner):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+    stderrors "errors"
     "net/http"
     "strconv"
     
     "github.com/gin-gonic/gin"
     
+    "gocom/main/internal/common/auth"
+    "gocom/main/internal/models"
+    "gocom/main/internal/seller/middleware"
     "gocom/main/internal/seller/services"
     "gocom/main/internal/common/errors"
+    "gocom/main/internal/common/validation"
 )
 
 type ProductHandler struct {
@@ -23,13 +28,7 @@ func NewProductHandler() *ProductHandler {
 // Create product
 // POST /v1/sellers/:id/products
 func (ph *ProductHandler) CreateProduct(c *gin.Context) {
-    sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
-        return
-    }
-    
-    // TODO: Validate seller owns this seller_id via JWT middleware
+    sellerID := middleware.GetSellerID(c)
     
     var req services.CreateProductRequest
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -37,9 +36,9 @@ func (ph *ProductHandler) CreateProduct(c *gin.Context) {
         return
     }
     
-    product, err := ph.ProductService.CreateProduct(uint(sellerID), &req)
+    product, err := ph.ProductService.CreateProduct(sellerID, &req)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        respondProductError(c, err)
         return
     }
     
@@ -53,11 +52,7 @@ func (ph *ProductHandler) CreateProduct(c *gin.Context) {
 // List seller products
 // GET /v1/sellers/:id/products
 func (ph *ProductHandler) ListProducts(c *gin.Context) {
-    sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
-        return
-    }
+    sellerID := middleware.GetSellerID(c)
     
     var filters services.ProductFilters
     if err := c.ShouldBindQuery(&filters); err != nil {
@@ -65,7 +60,7 @@ func (ph *ProductHandler) ListProducts(c *gin.Context) {
         return
     }
     
-    products, total, err := ph.ProductService.ListProducts(uint(sellerID), filters)
+    products, total, err := ph.ProductService.ListProducts(sellerID, filters)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
         return
@@ -91,8 +86,7 @@ func (ph *ProductHandler) GetProduct(c *gin.Context) {
         return
     }
     
-    // TODO: Get seller ID from JWT
-    sellerID := uint(1) // Placeholder
+    sellerID := middleware.GetSellerID(c)
     
     product, err := ph.ProductService.GetProduct(uint(productID), sellerID)
     if err != nil {
@@ -115,17 +109,280 @@ func (ph *ProductHandler) PublishProduct(c *gin.Context) {
         return
     }
     
-    // TODO: Get seller ID from JWT
-    sellerID := uint(1) // Placeholder
+    sellerID := middleware.GetSellerID(c)
+    principal, _ := auth.GetPrincipal(c)
+    
+    product, err := ph.ProductService.PublishProduct(uint(productID), sellerID, principal.UserID)
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    message := "Product submitted for review"
+    if product.Status == models.ProductStatusRejected {
+        message = "Product rejected by automatic checks, see rejection_reasons"
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    product,
+        "message": message,
+    })
+}
+
+// Get product moderation history
+// GET /v1/products/:id/moderation
+func (ph *ProductHandler) GetModerationHistory(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    history, err := ph.ProductService.ModerationService.History(uint(productID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    history,
+    })
+}
+
+// Get the content score breakdown with improvement suggestions
+// GET /v1/products/:id/score
+func (ph *ProductHandler) GetScoreBreakdown(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
     
-    if err := ph.ProductService.PublishProduct(uint(productID), sellerID); err != nil {
+    breakdown, err := ph.ProductService.GetScoreBreakdown(uint(productID), sellerID)
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    breakdown,
+    })
+}
+
+// Look up a SKU by code or barcode
+// GET /v1/sellers/:id/skus/lookup?code=...|barcode=...
+func (ph *ProductHandler) LookupSKU(c *gin.Context) {
+    sellerID := middleware.GetSellerID(c)
+    
+    sku, err := ph.ProductService.LookupSKU(sellerID, c.Query("code"), c.Query("barcode"))
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    sku,
+    })
+}
+
+// Update product fields
+// PATCH /v1/products/:id
+func (ph *ProductHandler) UpdateProduct(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    var req services.UpdateProductRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
     
+    sellerID := middleware.GetSellerID(c)
+    
+    product, err := ph.ProductService.UpdateProduct(uint(productID), sellerID, &req)
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "message": "Product published successfully",
+        "data":    product,
+        "message": "Product updated successfully",
     })
 }
 
+// Add SKU to product
+// POST /v1/products/:id/skus
+func (ph *ProductHandler) AddSKU(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    var req services.CreateSKURequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
+    
+    sku, err := ph.ProductService.AddSKU(uint(productID), sellerID, &req)
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "data":    sku,
+        "message": "SKU added successfully",
+    })
+}
+
+// Update SKU
+// PATCH /v1/products/:id/skus/:skuId
+func (ph *ProductHandler) UpdateSKU(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    skuID, err := strconv.ParseUint(c.Param("skuId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    var req services.UpdateSKURequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
+    
+    sku, err := ph.ProductService.UpdateSKU(uint(productID), sellerID, uint(skuID), &req)
+    if err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    sku,
+        "message": "SKU updated successfully",
+    })
+}
+
+// Deactivate SKU
+// POST /v1/products/:id/skus/:skuId/deactivate
+func (ph *ProductHandler) DeactivateSKU(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    skuID, err := strconv.ParseUint(c.Param("skuId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
+    
+    if err := ph.ProductService.DeactivateSKU(uint(productID), sellerID, uint(skuID)); err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "SKU deactivated successfully",
+    })
+}
+
+// Archive product
+// POST /v1/products/:id/archive
+func (ph *ProductHandler) ArchiveProduct(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
+    principal, _ := auth.GetPrincipal(c)
+    
+    if err := ph.ProductService.ArchiveProduct(uint(productID), sellerID, principal.UserID); err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Product archived successfully",
+    })
+}
+
+// Unarchive product
+// POST /v1/products/:id/unarchive
+func (ph *ProductHandler) UnarchiveProduct(c *gin.Context) {
+    productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+        return
+    }
+    
+    sellerID := middleware.GetSellerID(c)
+    principal, _ := auth.GetPrincipal(c)
+    
+    if err := ph.ProductService.UnarchiveProduct(uint(productID), sellerID, principal.UserID); err != nil {
+        respondProductError(c, err)
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Product moved back to draft",
+    })
+}
+
+func respondProductError(c *gin.Context, err error) {
+    var fieldErrs validation.FieldErrors
+    var scoreErr *services.ScoreTooLowError
+    switch {
+    case stderrors.As(err, &fieldErrs):
+        c.JSON(http.StatusBadRequest, errors.NewValidationError(fieldErrs))
+    case stderrors.As(err, &scoreErr):
+        c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": scoreErr.Breakdown})
+    case stderrors.Is(err, services.ErrProductNotFound), stderrors.Is(err, services.ErrSKUNotFound):
+        c.JSON(http.StatusNotFound, errors.ErrNotFound)
+    case stderrors.Is(err, services.ErrProductArchived), stderrors.Is(err, services.ErrProductNotArchived),
+        stderrors.Is(err, services.ErrLastActiveSKU), stderrors.Is(err, services.ErrProductInReview),
+        stderrors.Is(err, services.ErrProductUnchanged):
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+    case stderrors.Is(err, services.ErrSKUCodeTaken), stderrors.Is(err, services.ErrBarcodeTaken),
+        stderrors.Is(err, services.ErrDuplicateSKU):
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+    case stderrors.Is(err, services.ErrSellerNotApproved), stderrors.Is(err, services.ErrProductScoreTooLow):
+        c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+    case stderrors.Is(err, services.ErrInvalidCategory), stderrors.Is(err, services.ErrSKULookupFilter),
+        stderrors.Is(err, services.ErrInvalidSKUCode), stderrors.Is(err, validation.ErrInvalidBarcode),
+        stderrors.Is(err, validation.ErrInvalidBarcodeCheck):
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    default:
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    }
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+type SellerHandler struct {
+	OnboardingService *services.OnboardingService
+}
+
+func NewSellerHandler() *SellerHandler {
+	return &SellerHandler{
+		OnboardingService: services.NewOnboardingService(),
+	}
+}
+
+// Sign up a new seller
+// POST /v1/sellers
+func (sh *SellerHandler) RegisterSeller(c *gin.Context) {
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	var req services.RegisterSellerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seller, err := sh.OnboardingService.RegisterSeller(principal.UserID, &req)
+	if err != nil {
+		respondSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Seller registered, pending approval",
+	})
+}
+
+// Update seller profile
+// PATCH /v1/sellers/:id
+func (sh *SellerHandler) UpdateSeller(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	var req services.UpdateSellerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seller, err := sh.OnboardingService.UpdateSeller(sellerID, &req)
+	if err != nil {
+		respondSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Seller updated successfully",
+	})
+}
+
+// Update payout bank details
+// PUT /v1/sellers/:id/bank
+func (sh *SellerHandler) UpdateBankDetails(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	var req services.UpdateBankRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seller, err := sh.OnboardingService.UpdateBankDetails(sellerID, req.BankRef)
+	if err != nil {
+		respondSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Bank details updated",
+	})
+}
+
+// Get onboarding checklist
+// GET /v1/sellers/:id/onboarding
+func (sh *SellerHandler) GetOnboarding(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	checklist, err := sh.OnboardingService.GetChecklist(sellerID)
+	if err != nil {
+		respondSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checklist,
+	})
+}
+
+// Resubmit a rejected seller for review
+// POST /v1/sellers/:id/onboarding/resubmit
+func (sh *SellerHandler) Resubmit(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	seller, err := sh.OnboardingService.Resubmit(sellerID)
+	if err != nil {
+		respondSellerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    seller,
+		"message": "Seller resubmitted for review",
+	})
+}
+
+func respondSellerError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrSellerNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrGSTINTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrSellerLocked), stderrors.Is(err, services.ErrNotResubmittable),
+		stderrors.Is(err, services.ErrOnboardingIncomplete):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case isTaxIDError(err):
+		c.JSON(http.StatusBadRequest, errors.NewAPIError(http.StatusBadRequest, errors.ErrValidation.Message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func isTaxIDError(err error) bool {
+	for _, target := range []error{
+		validation.ErrInvalidPAN,
+		validation.ErrInvalidPANEntity,
+		validation.ErrInvalidGSTIN,
+		validation.ErrInvalidGSTINState,
+		validation.ErrInvalidGSTINCheck,
+		validation.ErrGSTINPANMismatch,
+	} {
+		if stderrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
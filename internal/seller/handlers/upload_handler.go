@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/models"
+	"gocom/main/internal/seller/middleware"
+	"gocom/main/internal/seller/services"
+)
+
+type UploadHandler struct {
+	UploadService *services.UploadService
+}
+
+func NewUploadHandler() *UploadHandler {
+	return &UploadHandler{
+		UploadService: services.NewUploadService(),
+	}
+}
+
+// Create a presigned direct upload
+// POST /v1/sellers/:id/uploads
+func (uh *UploadHandler) CreateUpload(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.ErrUnauthorized)
+		return
+	}
+
+	var req services.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !canUpload(c, req.Purpose) {
+		c.JSON(http.StatusForbidden, errors.ErrForbidden)
+		return
+	}
+
+	upload, err := uh.UploadService.CreateUpload(sellerID, principal.UserID, &req)
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    upload,
+	})
+}
+
+// Finalise a presigned direct upload
+// POST /v1/sellers/:id/uploads/:uploadId/finalise
+func (uh *UploadHandler) FinaliseUpload(c *gin.Context) {
+	sellerID := middleware.GetSellerID(c)
+
+	uploadID, err := strconv.ParseUint(c.Param("uploadId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	var req services.FinaliseUploadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	session, err := uh.UploadService.GetUpload(sellerID, uint(uploadID))
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+
+	if !canUpload(c, session.Purpose) {
+		c.JSON(http.StatusForbidden, errors.ErrForbidden)
+		return
+	}
+
+	result, err := uh.UploadService.FinaliseUpload(session, &req)
+	if err != nil {
+		respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "Upload finalised",
+	})
+}
+
+// The upload purpose decides which permission the member needs
+func canUpload(c *gin.Context, purpose string) bool {
+	permission := middleware.PermManageProfile
+	if purpose == models.UploadPurposeProductMedia {
+		permission = middleware.PermManageProducts
+	}
+	membership, ok := middleware.GetMembership(c)
+	return ok && middleware.HasPermission(membership.Role, permission)
+}
+
+func respondUploadError(c *gin.Context, err error) {
+	switch {
+	case stderrors.Is(err, services.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, errors.ErrNotFound)
+	case stderrors.Is(err, services.ErrUploadExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrUploadNotReceived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrUploadMismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrInvalidUploadPurpose), stderrors.Is(err, services.ErrUploadProduct):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case stderrors.Is(err, services.ErrKYCTooLarge), stderrors.Is(err, services.ErrKYCContentType),
+		stderrors.Is(err, services.ErrInvalidKYCType), stderrors.Is(err, services.ErrKYCEmpty):
+		respondKYCError(c, err)
+	default:
+		respondMediaError(c, err)
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/auth"
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/models"
+	"gocom/main/internal/seller/services"
+)
+
+const (
+	SellerIDKey   = "seller.id"
+	MembershipKey = "seller.membership"
+)
+
+// RequireSellerAccess checks the authenticated user is an active member of
+// the seller in the :id path parameter
+func RequireSellerAccess() gin.HandlerFunc {
+	membershipService := services.NewMembershipService()
+
+	return func(c *gin.Context) {
+		sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.AbortWithError(errors.ErrBadRequest.Code, errors.ErrBadRequest)
+			return
+		}
+
+		authorize(c, membershipService, uint(sellerID))
+	}
+}
+
+// RequireProductAccess checks the authenticated user is an active member of
+// the seller owning the product in the :id path parameter
+func RequireProductAccess() gin.HandlerFunc {
+	membershipService := services.NewMembershipService()
+
+	return func(c *gin.Context) {
+		productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.AbortWithError(errors.ErrBadRequest.Code, errors.ErrBadRequest)
+			return
+		}
+
+		sellerID, err := membershipService.GetProductSellerID(uint(productID))
+		if err != nil {
+			c.AbortWithError(errors.ErrNotFound.Code, errors.ErrNotFound)
+			return
+		}
+
+		authorize(c, membershipService, sellerID)
+	}
+}
+
+func authorize(c *gin.Context, membershipService *services.MembershipService, sellerID uint) {
+	principal, ok := auth.GetPrincipal(c)
+	if !ok {
+		c.AbortWithError(errors.ErrUnauthorized.Code, errors.ErrUnauthorized)
+		return
+	}
+
+	membership, err := membershipService.GetActiveMembership(sellerID, principal.UserID)
+	if err != nil {
+		c.AbortWithError(errors.ErrForbidden.Code, errors.ErrForbidden)
+		return
+	}
+
+	c.Set(SellerIDKey, sellerID)
+	c.Set(MembershipKey, membership)
+	c.Next()
+}
+
+// GetSellerID returns the seller ID resolved by the access middleware
+func GetSellerID(c *gin.Context) uint {
+	return c.GetUint(SellerIDKey)
+}
+
+// GetMembership returns the caller's membership resolved by the access middleware
+func GetMembership(c *gin.Context) (*models.SellerUser, bool) {
+	value, exists := c.Get(MembershipKey)
+	if !exists {
+		return nil, false
+	}
+	membership, ok := value.(*models.SellerUser)
+	return membership, ok
+}
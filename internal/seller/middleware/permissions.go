@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gocom/main/internal/common/errors"
+	"gocom/main/internal/models"
+)
+
+type Permission string
+
+// Seller permissions
+const (
+	PermViewMembers     Permission = "members:view"
+	PermManageMembers   Permission = "members:manage"
+	PermManageProfile   Permission = "profile:manage"
+	PermManageBank      Permission = "bank:manage"
+	PermViewProducts    Permission = "products:view"
+	PermManageProducts  Permission = "products:manage"
+	PermPublishProducts Permission = "products:publish"
+	PermManageShipments Permission = "shipments:manage"
+	PermViewFinance     Permission = "finance:view"
+)
+
+// Permission matrix for seller roles
+var rolePermissions = map[string][]Permission{
+	models.SellerRoleOwner: {
+		PermViewMembers,
+		PermManageMembers,
+		PermManageProfile,
+		PermManageBank,
+		PermViewProducts,
+		PermManageProducts,
+		PermPublishProducts,
+		PermManageShipments,
+		PermViewFinance,
+	},
+	models.SellerRoleCatalogManager: {
+		PermViewMembers,
+		PermViewProducts,
+		PermManageProducts,
+		PermPublishProducts,
+	},
+	models.SellerRoleFulfilment: {
+		PermViewMembers,
+		PermViewProducts,
+		PermManageShipments,
+	},
+	models.SellerRoleFinance: {
+		PermViewMembers,
+		PermViewProducts,
+		PermViewFinance,
+	},
+}
+
+// HasPermission reports whether a seller role grants the permission
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission rejects callers whose seller role lacks the permission.
+// Must run after RequireSellerAccess or RequireProductAccess.
+func RequirePermission(permission Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		membership, ok := GetMembership(c)
+		if !ok || !HasPermission(membership.Role, permission) {
+			c.AbortWithError(errors.ErrForbidden.Code, errors.ErrForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
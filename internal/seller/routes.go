@@ -3,24 +3,100 @@ package seller
 import (
 	"github.com/gin-gonic/gin"
 
+	"gocom/main/internal/common/auth"
 	"gocom/main/internal/seller/handlers"
+	"gocom/main/internal/seller/middleware"
 )
 
 func SetupRoutes(r *gin.Engine) {
 	// Initialize handlers
 	productHandler := handlers.NewProductHandler()
+	memberHandler := handlers.NewMemberHandler()
+	sellerHandler := handlers.NewSellerHandler()
+	kycHandler := handlers.NewKYCHandler()
+	mediaHandler := handlers.NewMediaHandler()
+	uploadHandler := handlers.NewUploadHandler()
+	importHandler := handlers.NewImportHandler()
+	exportHandler := handlers.NewExportHandler()
 
-	// API v1 group
-	v1 := r.Group("/v1")
+	// API v1 group, all seller routes require an authenticated user
+	v1 := r.Group("/v1", auth.AuthMiddleware())
+
+	// Seller-scoped routes, caller must be an active member of :id
+	sellerRoutes := v1.Group("/sellers/:id", middleware.RequireSellerAccess())
+
+	// Product routes, caller must be an active member of the owning seller
+	productRoutes := v1.Group("/products/:id", middleware.RequireProductAccess())
+
+	// Seller onboarding routes
+	{
+		v1.POST("/sellers", sellerHandler.RegisterSeller)
+		sellerRoutes.PATCH("", middleware.RequirePermission(middleware.PermManageProfile), sellerHandler.UpdateSeller)
+		sellerRoutes.PUT("/bank", middleware.RequirePermission(middleware.PermManageBank), sellerHandler.UpdateBankDetails)
+		sellerRoutes.GET("/onboarding", sellerHandler.GetOnboarding)
+		sellerRoutes.POST("/onboarding/resubmit", middleware.RequirePermission(middleware.PermManageProfile), sellerHandler.Resubmit)
+	}
+
+	// KYC routes
+	{
+		sellerRoutes.POST("/kyc", middleware.RequirePermission(middleware.PermManageProfile), kycHandler.UploadDocument)
+		sellerRoutes.GET("/kyc", middleware.RequirePermission(middleware.PermManageProfile), kycHandler.ListDocuments)
+	}
+
+	// Direct upload routes, permission depends on the upload purpose
+	{
+		sellerRoutes.POST("/uploads", uploadHandler.CreateUpload)
+		sellerRoutes.POST("/uploads/:uploadId/finalise", uploadHandler.FinaliseUpload)
+	}
 
 	// Product routes
 	{
 		// Seller-specific product routes
-		v1.POST("/sellers/:id/products", productHandler.CreateProduct)
-		v1.GET("/sellers/:id/products", productHandler.ListProducts)
+		sellerRoutes.POST("/products", middleware.RequirePermission(middleware.PermManageProducts), productHandler.CreateProduct)
+		sellerRoutes.GET("/products", middleware.RequirePermission(middleware.PermViewProducts), productHandler.ListProducts)
+		sellerRoutes.GET("/skus/lookup", middleware.RequirePermission(middleware.PermViewProducts), productHandler.LookupSKU)
+
+		// Bulk import routes
+		sellerRoutes.POST("/products/import", middleware.RequirePermission(middleware.PermManageProducts), importHandler.CreateImport)
+		sellerRoutes.GET("/imports", middleware.RequirePermission(middleware.PermViewProducts), importHandler.ListImports)
+		sellerRoutes.GET("/imports/:jobId", middleware.RequirePermission(middleware.PermViewProducts), importHandler.GetImport)
+
+		// Catalog export routes
+		sellerRoutes.POST("/products/export", middleware.RequirePermission(middleware.PermViewProducts), exportHandler.CreateExport)
+		sellerRoutes.GET("/exports", middleware.RequirePermission(middleware.PermViewProducts), exportHandler.ListExports)
+		sellerRoutes.GET("/exports/:jobId", middleware.RequirePermission(middleware.PermViewProducts), exportHandler.GetExport)
 
 		// Product management routes
-		v1.GET("/products/:id", productHandler.GetProduct)
-		v1.POST("/products/:id/publish", productHandler.PublishProduct)
+		productRoutes.GET("", middleware.RequirePermission(middleware.PermViewProducts), productHandler.GetProduct)
+		productRoutes.PATCH("", middleware.RequirePermission(middleware.PermManageProducts), productHandler.UpdateProduct)
+		productRoutes.POST("/publish", middleware.RequirePermission(middleware.PermPublishProducts), productHandler.PublishProduct)
+		productRoutes.GET("/moderation", middleware.RequirePermission(middleware.PermViewProducts), productHandler.GetModerationHistory)
+		productRoutes.GET("/score", middleware.RequirePermission(middleware.PermViewProducts), productHandler.GetScoreBreakdown)
+		productRoutes.POST("/archive", middleware.RequirePermission(middleware.PermManageProducts), productHandler.ArchiveProduct)
+		productRoutes.POST("/unarchive", middleware.RequirePermission(middleware.PermManageProducts), productHandler.UnarchiveProduct)
+
+		// SKU routes
+		productRoutes.POST("/skus", middleware.RequirePermission(middleware.PermManageProducts), productHandler.AddSKU)
+		productRoutes.PATCH("/skus/:skuId", middleware.RequirePermission(middleware.PermManageProducts), productHandler.UpdateSKU)
+		productRoutes.POST("/skus/:skuId/deactivate", middleware.RequirePermission(middleware.PermManageProducts), productHandler.DeactivateSKU)
+
+		// Product media routes
+		productRoutes.GET("/media", middleware.RequirePermission(middleware.PermViewProducts), mediaHandler.ListMedia)
+		productRoutes.POST("/media", middleware.RequirePermission(middleware.PermManageProducts), mediaHandler.UploadMedia)
+		productRoutes.PUT("/media/order", middleware.RequirePermission(middleware.PermManageProducts), mediaHandler.ReorderMedia)
+		productRoutes.PATCH("/media/:mediaId", middleware.RequirePermission(middleware.PermManageProducts), mediaHandler.UpdateMedia)
+		productRoutes.DELETE("/media/:mediaId", middleware.RequirePermission(middleware.PermManageProducts), mediaHandler.DeleteMedia)
+	}
+
+	// Team member routes
+	{
+		sellerRoutes.GET("/members", middleware.RequirePermission(middleware.PermViewMembers), memberHandler.ListMembers)
+		sellerRoutes.POST("/members", middleware.RequirePermission(middleware.PermManageMembers), memberHandler.InviteMember)
+		sellerRoutes.PATCH("/members/:memberId", middleware.RequirePermission(middleware.PermManageMembers), memberHandler.ChangeRole)
+		sellerRoutes.POST("/members/:memberId/suspend", middleware.RequirePermission(middleware.PermManageMembers), memberHandler.SuspendMember)
+		sellerRoutes.DELETE("/members/:memberId", middleware.RequirePermission(middleware.PermManageMembers), memberHandler.RemoveMember)
+
+		// Invited users are not active members yet, so this skips RequireSellerAccess
+		v1.POST("/sellers/:id/invitation/accept", memberHandler.AcceptInvitation)
 	}
 }
@@ -0,0 +1,83 @@
+package services
+
+import "strings"
+
+// Catalog file columns, shared by import and export. There is one row per
+// SKU; rows with the same parent_key form one product, whose fields are
+// taken from its first row. Rows whose sku_code the seller already has
+// update that SKU and its product instead, so an export can be edited and
+// imported back. Product attributes go in "attr.<name>"
+// columns, SKU variant attributes in "variant.<name>" columns.
+const (
+	CatalogColumnParentKey   = "parent_key"
+	CatalogColumnCategoryID  = "category_id"
+	CatalogColumnTitle       = "title"
+	CatalogColumnDescription = "description"
+	CatalogColumnBrand       = "brand"
+	CatalogColumnSKUCode     = "sku_code"
+	CatalogColumnPriceMRP    = "price_mrp"
+	CatalogColumnPriceSell   = "price_sell"
+	CatalogColumnTaxPct      = "tax_pct"
+	CatalogColumnBarcode     = "barcode"
+	CatalogColumnStatus      = "status"
+	CatalogColumnInventory   = "inventory"  // Available units across locations
+	CatalogColumnMediaURLs   = "media_urls" // Presigned links separated by CatalogListSeparator
+
+	CatalogListSeparator = "|"
+
+	CatalogAttributePrefix = "attr."
+	CatalogVariantPrefix   = "variant."
+)
+
+// CatalogColumns lists the fixed columns in file order
+var CatalogColumns = []string{
+	CatalogColumnParentKey, CatalogColumnCategoryID, CatalogColumnTitle, CatalogColumnDescription,
+	CatalogColumnBrand, CatalogColumnSKUCode, CatalogColumnPriceMRP, CatalogColumnPriceSell,
+	CatalogColumnTaxPct, CatalogColumnBarcode,
+}
+
+// CatalogReadOnlyColumns are written by export for reference and ignored
+// by import, so an exported file can be imported as is
+var CatalogReadOnlyColumns = []string{
+	CatalogColumnStatus, CatalogColumnInventory, CatalogColumnMediaURLs,
+}
+
+func isCatalogColumn(name string) bool {
+	if strings.HasPrefix(name, CatalogAttributePrefix) || strings.HasPrefix(name, CatalogVariantPrefix) {
+		return true
+	}
+	for _, column := range CatalogColumns {
+		if name == column {
+			return true
+		}
+	}
+	return isReadOnlyColumn(name)
+}
+
+func isReadOnlyColumn(name string) bool {
+	for _, column := range CatalogReadOnlyColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Spreadsheet apps run a cell starting with one of these as a formula
+const catalogFormulaPrefixes = "=+-@"
+
+// Quote a cell that would run as a formula when the file is opened in a
+// spreadsheet app; import removes the quote again
+func escapeCatalogCell(value string) string {
+	if value != "" && strings.IndexByte(catalogFormulaPrefixes, value[0]) >= 0 {
+		return "'" + value
+	}
+	return value
+}
+
+func unescapeCatalogCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.IndexByte(catalogFormulaPrefixes, value[1]) >= 0 {
+		return value[1:]
+	}
+	return value
+}
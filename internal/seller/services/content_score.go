@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/models"
+)
+
+// Score components
+const (
+	ScoreComponentTitle       = "title"
+	ScoreComponentDescription = "description"
+	ScoreComponentBrand       = "brand"
+	ScoreComponentSKUPricing  = "sku_pricing"
+	ScoreComponentMedia       = "media"
+	ScoreComponentAttributes  = "attributes"
+)
+
+// ScoreBreakdown explains a content quality score component by component
+type ScoreBreakdown struct {
+	Score           int              `json:"score"`
+	MaxScore        int              `json:"max_score"`
+	MinPublishScore int              `json:"min_publish_score"`
+	Publishable     bool             `json:"publishable"`
+	Components      []ScoreComponent `json:"components"`
+}
+
+// ScoreComponent is the share of the score earned by one aspect of the
+// listing, with suggestions for every point still missing
+type ScoreComponent struct {
+	Name        string   `json:"name"`
+	Points      int      `json:"points"`
+	MaxPoints   int      `json:"max_points"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ScoreTooLowError is returned when a product below its category's publish
+// threshold is submitted; it carries the breakdown so the seller knows
+// what to improve
+type ScoreTooLowError struct {
+	Breakdown *ScoreBreakdown
+}
+
+func (e *ScoreTooLowError) Error() string {
+	return fmt.Sprintf("%s: %d of the required %d", ErrProductScoreTooLow, e.Breakdown.Score, e.Breakdown.MinPublishScore)
+}
+
+func (e *ScoreTooLowError) Unwrap() error {
+	return ErrProductScoreTooLow
+}
+
+// Get the score breakdown of a product. The stored score is refreshed on
+// the way, since category scoring rules may have changed since it was
+// last calculated.
+func (ps *ProductService) GetScoreBreakdown(productID, sellerID uint) (*ScoreBreakdown, error) {
+	if _, err := ps.findProduct(ps.DB, productID, sellerID); err != nil {
+		return nil, err
+	}
+
+	var breakdown *ScoreBreakdown
+	err := ps.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		breakdown, err = ps.recalculateScore(tx, productID, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// Score a product against the rules and attribute schema of its category
+func (ps *ProductService) scoreContent(tx *gorm.DB, product *models.Product, skus []models.SKU, media []models.Media) (*ScoreBreakdown, error) {
+	var category models.Category
+	if err := tx.First(&category, product.CategoryID).Error; err != nil {
+		return nil, ErrInvalidCategory
+	}
+	rules, err := ps.scoringRules(tx, &category)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := category.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := product.GetAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	components := []ScoreComponent{
+		scoreLength(ScoreComponentTitle, product.Title, rules.Title),
+		scoreLength(ScoreComponentDescription, product.Description, rules.Description),
+		scoreBrand(product.Brand, rules),
+		scoreSKUPricing(skus, rules),
+		scoreMedia(media, rules),
+		scoreAttributes(schema, attrs, rules),
+	}
+
+	breakdown := &ScoreBreakdown{
+		MaxScore:        rules.MaxScore(),
+		MinPublishScore: rules.MinPublishScore,
+		Components:      components,
+	}
+	for _, component := range components {
+		breakdown.Score += component.Points
+	}
+	breakdown.Publishable = breakdown.Score >= breakdown.MinPublishScore
+
+	return breakdown, nil
+}
+
+// Resolve the scoring rules of a category: its own, else the nearest
+// ancestor's, else the defaults
+func (ps *ProductService) scoringRules(tx *gorm.DB, category *models.Category) (*models.ScoringRules, error) {
+	current := category
+	for depth := 0; current != nil && depth < maxCategoryDepth; depth++ {
+		rules, err := current.GetScoringRules()
+		if err != nil {
+			return nil, err
+		}
+		if rules != nil {
+			return rules, nil
+		}
+		if current.ParentID == nil {
+			break
+		}
+		var parent models.Category
+		if err := tx.First(&parent, *current.ParentID).Error; err != nil {
+			break
+		}
+		current = &parent
+	}
+	return models.DefaultScoringRules(), nil
+}
+
+// Guards the ancestor walk against a corrupted parent chain
+const maxCategoryDepth = 32
+
+func scoreLength(name, value string, rule models.LengthRule) ScoreComponent {
+	component := ScoreComponent{Name: name, MaxPoints: rule.Points}
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
+
+	switch {
+	case length >= rule.IdealMin && length <= rule.IdealMax:
+		component.Points = rule.Points
+		return component
+	case length > rule.IdealMax:
+		component.Points = rule.PartialPoints
+		component.Suggestions = append(component.Suggestions,
+			fmt.Sprintf("Shorten the %s to at most %d characters (currently %d)", name, rule.IdealMax, length))
+		return component
+	case length >= rule.MinLength:
+		component.Points = rule.PartialPoints
+	}
+	component.Suggestions = append(component.Suggestions,
+		fmt.Sprintf("Lengthen the %s to at least %d characters (currently %d)", name, rule.IdealMin, length))
+	return component
+}
+
+func scoreBrand(brand string, rules *models.ScoringRules) ScoreComponent {
+	component := ScoreComponent{Name: ScoreComponentBrand, MaxPoints: rules.BrandPoints}
+	if strings.TrimSpace(brand) != "" {
+		component.Points = rules.BrandPoints
+	} else if rules.BrandPoints > 0 {
+		component.Suggestions = append(component.Suggestions, "Add the brand of the product")
+	}
+	return component
+}
+
+func scoreSKUPricing(skus []models.SKU, rules *models.ScoringRules) ScoreComponent {
+	component := ScoreComponent{Name: ScoreComponentSKUPricing, MaxPoints: rules.SKUPoints + rules.DiscountPoints}
+	if len(skus) == 0 {
+		component.Suggestions = append(component.Suggestions, "Add at least one active SKU")
+		if rules.DiscountPoints > 0 {
+			component.Suggestions = append(component.Suggestions, "Price at least one SKU below its MRP")
+		}
+		return component
+	}
+
+	component.Points = rules.SKUPoints
+	for _, sku := range skus {
+		if sku.PriceMRP.GreaterThan(sku.PriceSell) && sku.PriceSell.GreaterThan(decimal.Zero) {
+			component.Points += rules.DiscountPoints
+			return component
+		}
+	}
+	if rules.DiscountPoints > 0 {
+		component.Suggestions = append(component.Suggestions, "Set an MRP above the selling price on at least one SKU")
+	}
+	return component
+}
+
+func scoreMedia(media []models.Media, rules *models.ScoringRules) ScoreComponent {
+	component := ScoreComponent{Name: ScoreComponentMedia, MaxPoints: rules.MediaPoints}
+	images := 0
+	for _, m := range media {
+		if m.Type == models.MediaTypeImage {
+			images++
+		}
+	}
+
+	switch {
+	case images >= rules.MinImages && images > 0:
+		component.Points = rules.MediaPoints
+		return component
+	case images > 0:
+		component.Points = rules.MediaPartialPoints
+	}
+	if rules.MediaPoints > 0 {
+		missing := rules.MinImages - images
+		if missing < 1 {
+			missing = 1
+		}
+		component.Suggestions = append(component.Suggestions,
+			fmt.Sprintf("Add %d more image(s); %d are needed for full points", missing, max(rules.MinImages, 1)))
+	}
+	return component
+}
+
+// Attribute points are awarded in proportion to the product-level schema
+// attributes filled in; a category without any earns them outright
+func scoreAttributes(schema *models.CategorySchema, attrs models.Attributes, rules *models.ScoringRules) ScoreComponent {
+	component := ScoreComponent{Name: ScoreComponentAttributes, MaxPoints: rules.AttributePoints}
+
+	var total int
+	var missing []string
+	for _, def := range schema.Attributes {
+		if def.Variant {
+			continue
+		}
+		total++
+		if value, ok := attrs[def.Name]; !ok || value == nil || value == "" {
+			missing = append(missing, def.Name)
+		}
+	}
+	if total == 0 {
+		component.Points = rules.AttributePoints
+		return component
+	}
+
+	component.Points = rules.AttributePoints * (total - len(missing)) / total
+	if len(missing) > 0 && rules.AttributePoints > 0 {
+		component.Suggestions = append(component.Suggestions,
+			fmt.Sprintf("Fill in the attributes: %s", strings.Join(missing, ", ")))
+	}
+	return component
+}
@@ -0,0 +1,564 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+const catalogExportsBucket = "catalog-exports"
+
+// Products are read in batches so large catalogs are streamed to disk
+// rather than held in memory
+const exportBatchSize = 200
+
+// Media links in an export stay valid this long, the most S3 presigning
+// allows, since files are often imported or shared days later
+const exportMediaURLExpiry = 7 * 24 * time.Hour
+
+var (
+	ErrExportNotFound = errors.New("export job not found")
+	ErrExportFormat   = errors.New("unsupported export format, use csv or jsonl")
+	ErrExportStatus   = errors.New("invalid product status filter")
+)
+
+var productStatusNames = map[int]string{
+	models.ProductStatusDraft:         "draft",
+	models.ProductStatusPublished:     "published",
+	models.ProductStatusRejected:      "rejected",
+	models.ProductStatusArchived:      "archived",
+	models.ProductStatusPendingReview: "pending_review",
+}
+
+type ExportService struct {
+	DB    *gorm.DB
+	Store storage.ObjectStore
+}
+
+func NewExportService() *ExportService {
+	return &ExportService{
+		DB:    db.GetDB(),
+		Store: storage.GetStore(),
+	}
+}
+
+// Queue a catalog export of the seller's products
+func (es *ExportService) CreateExport(sellerID, userID uint, req *CreateExportRequest) (*models.ExportJob, error) {
+	format := req.Format
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if format != models.ExportFormatCSV && format != models.ExportFormatJSONL {
+		return nil, ErrExportFormat
+	}
+	if req.Status != nil {
+		if _, ok := productStatusNames[*req.Status]; !ok {
+			return nil, ErrExportStatus
+		}
+	}
+
+	job := &models.ExportJob{
+		SellerID:         sellerID,
+		UserID:           userID,
+		Format:           format,
+		FilterStatus:     req.Status,
+		FilterCategoryID: req.CategoryID,
+		Status:           models.ExportStatusPending,
+	}
+	if err := es.DB.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get an export job of the seller with a download link once it completed
+func (es *ExportService) GetExport(sellerID, jobID uint) (*ExportJobResponse, error) {
+	var job models.ExportJob
+	if err := es.DB.Where("id = ? AND seller_id = ?", jobID, sellerID).First(&job).Error; err != nil {
+		return nil, ErrExportNotFound
+	}
+
+	response := &ExportJobResponse{ExportJob: job}
+	if job.Status == models.ExportStatusCompleted && job.ObjectName != "" {
+		url, err := es.Store.PresignGet(catalogExportsBucket, job.ObjectName, config.AppConfig.PresignExpiry)
+		if err != nil {
+			return nil, err
+		}
+		response.DownloadURL = url
+	}
+	return response, nil
+}
+
+// List export jobs of the seller, newest first
+func (es *ExportService) ListExports(sellerID uint, page, limit int) ([]models.ExportJob, int64, error) {
+	var jobs []models.ExportJob
+	var total int64
+
+	query := es.DB.Model(&models.ExportJob{}).Where("seller_id = ?", sellerID)
+	query.Count(&total)
+
+	err := query.
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+// ProcessPendingExports claims and runs pending jobs, oldest first, until
+// none are left
+func (es *ExportService) ProcessPendingExports() error {
+	for {
+		var job models.ExportJob
+		err := es.DB.Where("status = ?", models.ExportStatusPending).Order("id").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Another worker may have claimed the job in the meantime
+		now := time.Now()
+		result := es.DB.Model(&models.ExportJob{}).
+			Where("id = ? AND status = ?", job.ID, models.ExportStatusPending).
+			Updates(map[string]interface{}{"status": models.ExportStatusProcessing, "started_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		job.Status = models.ExportStatusProcessing
+		job.StartedAt = &now
+
+		if err := es.runExport(&job); err != nil {
+			log.Printf("Export job %d failed: %v", job.ID, err)
+			es.finishExport(&job, models.ExportStatusFailed, err.Error())
+		}
+	}
+}
+
+// StartExportWorker requeues jobs cut off by a previous run, then polls
+// for pending jobs every interval. Unlike imports, exports have no side
+// effects and can simply run again.
+func (es *ExportService) StartExportWorker(interval time.Duration) {
+	es.DB.Model(&models.ExportJob{}).
+		Where("status = ? AND updated_at < ?", models.ExportStatusProcessing, time.Now().Add(-jobStaleAfter)).
+		Update("status", models.ExportStatusPending)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := es.ProcessPendingExports(); err != nil {
+				log.Printf("Export worker failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Write the export to a temp file batch by batch, then upload it
+func (es *ExportService) runExport(job *models.ExportJob) error {
+	file, err := os.CreateTemp("", "catalog-export-*."+job.Format)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	var writer exportWriter
+	if job.Format == models.ExportFormatJSONL {
+		writer = &jsonlExportWriter{encoder: json.NewEncoder(buffered)}
+	} else {
+		// The CSV header lists every attribute, which takes a first pass
+		productAttrs, variantAttrs, err := es.attributeColumns(job)
+		if err != nil {
+			return err
+		}
+		writer = newCSVExportWriter(buffered, productAttrs, variantAttrs)
+	}
+
+	var products []models.Product
+	result := es.exportQuery(job).
+		Preload("SKUs", "is_active = ?", true).
+		Preload("SKUs.Inventory").
+		Preload("Media", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort ASC, id ASC")
+		}).
+		FindInBatches(&products, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			for i := range products {
+				mediaURLs, err := es.mediaURLs(&products[i])
+				if err != nil {
+					return err
+				}
+				rows, err := writer.Write(&products[i], mediaURLs)
+				if err != nil {
+					return err
+				}
+				job.ProductCount++
+				job.RowCount += rows
+			}
+			return es.DB.Model(job).Updates(map[string]interface{}{
+				"product_count": job.ProductCount,
+				"row_count":     job.RowCount,
+			}).Error
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	contentType := "text/csv"
+	if job.Format == models.ExportFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	objectName := fmt.Sprintf("sellers/%d/catalog-%d.%s", job.SellerID, job.ID, job.Format)
+	if _, err := es.Store.Put(catalogExportsBucket, objectName, file, info.Size(), contentType); err != nil {
+		return err
+	}
+	job.ObjectName = objectName
+	es.DB.Model(job).Update("object_name", objectName)
+
+	es.finishExport(job, models.ExportStatusCompleted, "")
+	return nil
+}
+
+func (es *ExportService) finishExport(job *models.ExportJob, status int, message string) {
+	now := time.Now()
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+	es.DB.Model(job).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       message,
+		"finished_at": now,
+	})
+}
+
+func (es *ExportService) exportQuery(job *models.ExportJob) *gorm.DB {
+	query := es.DB.Model(&models.Product{}).Where("seller_id = ?", job.SellerID)
+	if job.FilterStatus != nil {
+		query = query.Where("status = ?", *job.FilterStatus)
+	}
+	if job.FilterCategoryID != nil {
+		query = query.Where("category_id = ?", *job.FilterCategoryID)
+	}
+	return query
+}
+
+// Collect the sorted attribute names used by the exported products and
+// their active SKUs
+func (es *ExportService) attributeColumns(job *models.ExportJob) ([]string, []string, error) {
+	productAttrs := map[string]bool{}
+	variantAttrs := map[string]bool{}
+
+	var products []models.Product
+	result := es.exportQuery(job).
+		Select("id", "attributes").
+		Preload("SKUs", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "product_id", "attributes").Where("is_active = ?", true)
+		}).
+		FindInBatches(&products, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, product := range products {
+				attrs, err := product.GetAttributes()
+				if err != nil {
+					return err
+				}
+				for name := range attrs {
+					productAttrs[name] = true
+				}
+				for _, sku := range product.SKUs {
+					attrs, err := sku.GetAttributes()
+					if err != nil {
+						return err
+					}
+					for name := range attrs {
+						variantAttrs[name] = true
+					}
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+
+	return sortedNames(productAttrs), sortedNames(variantAttrs), nil
+}
+
+// Links to the media of a product that can be fetched from outside
+func (es *ExportService) mediaURLs(product *models.Product) ([]string, error) {
+	urls := make([]string, 0, len(product.Media))
+	for i := range product.Media {
+		if err := storage.ResolveMediaLinks(es.Store, &product.Media[i], exportMediaURLExpiry); err != nil {
+			return nil, err
+		}
+		urls = append(urls, product.Media[i].URL)
+	}
+	return urls, nil
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exportWriter writes one product, returning the number of records written
+type exportWriter interface {
+	Write(product *models.Product, mediaURLs []string) (int, error)
+	Flush() error
+}
+
+// csvExportWriter writes the bulk import layout: one row per active SKU,
+// product fields repeated on every row, read-only columns at the end. A
+// product without active SKUs gets one row with the SKU columns empty.
+type csvExportWriter struct {
+	writer       *csv.Writer
+	productAttrs []string
+	variantAttrs []string
+	headerDone   bool
+}
+
+func newCSVExportWriter(w io.Writer, productAttrs, variantAttrs []string) *csvExportWriter {
+	return &csvExportWriter{
+		writer:       csv.NewWriter(w),
+		productAttrs: productAttrs,
+		variantAttrs: variantAttrs,
+	}
+}
+
+func (cw *csvExportWriter) header() []string {
+	header := append([]string{}, CatalogColumns...)
+	for _, name := range cw.productAttrs {
+		header = append(header, CatalogAttributePrefix+name)
+	}
+	for _, name := range cw.variantAttrs {
+		header = append(header, CatalogVariantPrefix+name)
+	}
+	return append(header, CatalogReadOnlyColumns...)
+}
+
+func (cw *csvExportWriter) Write(product *models.Product, mediaURLs []string) (int, error) {
+	if !cw.headerDone {
+		if err := cw.writer.Write(cw.header()); err != nil {
+			return 0, err
+		}
+		cw.headerDone = true
+	}
+
+	productAttrs, err := product.GetAttributes()
+	if err != nil {
+		return 0, err
+	}
+	if len(product.SKUs) == 0 {
+		return 1, cw.writer.Write(cw.record(product, productAttrs, nil, nil, mediaURLs))
+	}
+	for i := range product.SKUs {
+		variantAttrs, err := product.SKUs[i].GetAttributes()
+		if err != nil {
+			return 0, err
+		}
+		record := cw.record(product, productAttrs, &product.SKUs[i], variantAttrs, mediaURLs)
+		if err := cw.writer.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	return len(product.SKUs), nil
+}
+
+// One row of the file; a nil SKU leaves the SKU columns empty
+func (cw *csvExportWriter) record(product *models.Product, productAttrs models.Attributes, sku *models.SKU, variantAttrs models.Attributes, mediaURLs []string) []string {
+	skuCells := make([]string, 5)
+	inventory := ""
+	if sku != nil {
+		skuCells = []string{sku.SKUCode, sku.PriceMRP.String(), sku.PriceSell.String(), sku.TaxPct.String(), sku.Barcode}
+		inventory = strconv.Itoa(availableUnits(sku.Inventory))
+	}
+
+	record := []string{
+		strconv.FormatUint(uint64(product.ID), 10),
+		strconv.FormatUint(uint64(product.CategoryID), 10),
+		product.Title,
+		product.Description,
+		product.Brand,
+	}
+	record = append(record, skuCells...)
+	for _, name := range cw.productAttrs {
+		record = append(record, formatAttribute(productAttrs[name]))
+	}
+	for _, name := range cw.variantAttrs {
+		record = append(record, formatAttribute(variantAttrs[name]))
+	}
+	record = append(record,
+		productStatusNames[product.Status],
+		inventory,
+		strings.Join(mediaURLs, CatalogListSeparator),
+	)
+	for i := range record {
+		record[i] = escapeCatalogCell(record[i])
+	}
+	return record
+}
+
+func (cw *csvExportWriter) Flush() error {
+	// An empty export still gets a header so it can be used as a template
+	if !cw.headerDone {
+		if err := cw.writer.Write(cw.header()); err != nil {
+			return err
+		}
+		cw.headerDone = true
+	}
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// jsonlExportWriter writes one JSON document per product
+type jsonlExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (jw *jsonlExportWriter) Write(product *models.Product, mediaURLs []string) (int, error) {
+	attrs, err := product.GetAttributes()
+	if err != nil {
+		return 0, err
+	}
+	record := ExportProduct{
+		ID:          product.ID,
+		CategoryID:  product.CategoryID,
+		Title:       product.Title,
+		Description: product.Description,
+		Brand:       product.Brand,
+		Status:      productStatusNames[product.Status],
+		Attributes:  attrs,
+		SKUs:        make([]ExportSKU, 0, len(product.SKUs)),
+		MediaURLs:   mediaURLs,
+	}
+	for _, sku := range product.SKUs {
+		skuAttrs, err := sku.GetAttributes()
+		if err != nil {
+			return 0, err
+		}
+		record.SKUs = append(record.SKUs, ExportSKU{
+			SKUCode:    sku.SKUCode,
+			Attributes: skuAttrs,
+			PriceMRP:   sku.PriceMRP,
+			PriceSell:  sku.PriceSell,
+			TaxPct:     sku.TaxPct,
+			Barcode:    sku.Barcode,
+			Inventory:  availableUnits(sku.Inventory),
+		})
+	}
+	return 1, jw.encoder.Encode(record)
+}
+
+func (jw *jsonlExportWriter) Flush() error {
+	return nil
+}
+
+// Units that can still be sold across all locations
+func availableUnits(inventory []models.Inventory) int {
+	units := 0
+	for _, location := range inventory {
+		if available := location.OnHand - location.Reserved; available > 0 {
+			units += available
+		}
+	}
+	return units
+}
+
+// Format an attribute value the way bulk import parses it back
+func formatAttribute(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// Request DTOs
+type CreateExportRequest struct {
+	Format     string `json:"format"` // csv (default) or jsonl
+	Status     *int   `json:"status"`
+	CategoryID *uint  `json:"category_id"`
+}
+
+type ExportFilters struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+// Response DTOs
+type ExportJobResponse struct {
+	models.ExportJob
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// ExportProduct is one line of a JSON Lines export
+type ExportProduct struct {
+	ID          uint              `json:"id"`
+	CategoryID  uint              `json:"category_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Brand       string            `json:"brand"`
+	Status      string            `json:"status"`
+	Attributes  models.Attributes `json:"attributes"`
+	SKUs        []ExportSKU       `json:"skus"`
+	MediaURLs   []string          `json:"media_urls"`
+}
+
+type ExportSKU struct {
+	SKUCode    string            `json:"sku_code"`
+	Attributes models.Attributes `json:"attributes"`
+	PriceMRP   decimal.Decimal   `json:"price_mrp"`
+	PriceSell  decimal.Decimal   `json:"price_sell"`
+	TaxPct     decimal.Decimal   `json:"tax_pct"`
+	Barcode    string            `json:"barcode"`
+	Inventory  int               `json:"inventory"`
+}
@@ -0,0 +1,753 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/spreadsheet"
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+const catalogImportsBucket = "catalog-imports"
+
+// A background job still processing this long after its last progress
+// update was cut off by a restart
+const jobStaleAfter = 15 * time.Minute
+
+var (
+	ErrImportNotFound    = errors.New("import job not found")
+	ErrImportTooLarge    = errors.New("import file exceeds the size limit")
+	ErrImportEmpty       = errors.New("import file has no data rows")
+	ErrImportTooManyRows = errors.New("import file has too many rows")
+	ErrImportColumns     = errors.New("import file has missing or unknown columns")
+)
+
+var requiredImportColumns = []string{
+	CatalogColumnParentKey, CatalogColumnCategoryID, CatalogColumnTitle, CatalogColumnDescription,
+	CatalogColumnPriceMRP, CatalogColumnPriceSell,
+}
+
+// Product-level columns must agree across the rows of a product
+var productImportColumns = []string{
+	CatalogColumnCategoryID, CatalogColumnTitle, CatalogColumnDescription, CatalogColumnBrand,
+}
+
+// Request fields reported by the binding validator, mapped to columns
+var importFieldColumns = map[string]string{
+	"CategoryID":  CatalogColumnCategoryID,
+	"Title":       CatalogColumnTitle,
+	"Description": CatalogColumnDescription,
+	"Brand":       CatalogColumnBrand,
+	"SKUs":        CatalogColumnParentKey,
+	"SKUCode":     CatalogColumnSKUCode,
+	"PriceMRP":    CatalogColumnPriceMRP,
+	"PriceSell":   CatalogColumnPriceSell,
+	"TaxPct":      CatalogColumnTaxPct,
+	"Barcode":     CatalogColumnBarcode,
+}
+
+var skuFieldPattern = regexp.MustCompile(`^skus\[(\d+)\]\.(.+)$`)
+var skuNamespacePattern = regexp.MustCompile(`SKUs\[(\d+)\]`)
+
+type ImportService struct {
+	DB             *gorm.DB
+	Store          storage.ObjectStore
+	ProductService *ProductService
+}
+
+func NewImportService() *ImportService {
+	return &ImportService{
+		DB:             db.GetDB(),
+		Store:          storage.GetStore(),
+		ProductService: NewProductService(),
+	}
+}
+
+// importRow is one non-blank data row keyed by column; Line is its line
+// in the file so errors can be located by the seller
+type importRow struct {
+	Line  int
+	Cells map[string]string
+}
+
+type importGroup struct {
+	Key  string
+	Rows []importRow
+}
+
+type importRowError struct {
+	Line      int
+	ParentKey string
+	Column    string
+	Message   string
+}
+
+// Create an import job for an uploaded file. The header and row count are
+// checked right away; the rows themselves are processed by the worker.
+func (is *ImportService) CreateImport(sellerID, userID uint, fileName string, data []byte) (*models.ImportJob, error) {
+	if int64(len(data)) > config.AppConfig.ImportMaxBytes {
+		return nil, ErrImportTooLarge
+	}
+	format, err := spreadsheet.FormatFromName(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseImportFile(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(rows) > config.AppConfig.ImportMaxRows {
+		return nil, fmt.Errorf("%w: %d rows, the limit is %d", ErrImportTooManyRows, len(rows), config.AppConfig.ImportMaxRows)
+	}
+
+	objectName := fmt.Sprintf("sellers/%d/%d/source.%s", sellerID, time.Now().UnixNano(), format)
+	contentType := "text/csv"
+	if format == spreadsheet.FormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	if _, err := is.Store.Put(catalogImportsBucket, objectName, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		SellerID:     sellerID,
+		UserID:       userID,
+		FileName:     fileName,
+		Format:       format,
+		SourceObject: objectName,
+		Status:       models.ImportStatusPending,
+		TotalRows:    len(rows),
+	}
+	if err := is.DB.Create(job).Error; err != nil {
+		is.Store.Delete(catalogImportsBucket, objectName)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get an import job of the seller with its progress and a link to the
+// error report once there is one
+func (is *ImportService) GetImport(sellerID, jobID uint) (*ImportJobResponse, error) {
+	var job models.ImportJob
+	if err := is.DB.Where("id = ? AND seller_id = ?", jobID, sellerID).First(&job).Error; err != nil {
+		return nil, ErrImportNotFound
+	}
+	return is.newImportJobResponse(&job)
+}
+
+// List import jobs of the seller, newest first
+func (is *ImportService) ListImports(sellerID uint, page, limit int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+
+	query := is.DB.Model(&models.ImportJob{}).Where("seller_id = ?", sellerID)
+	query.Count(&total)
+
+	err := query.
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+func (is *ImportService) newImportJobResponse(job *models.ImportJob) (*ImportJobResponse, error) {
+	response := &ImportJobResponse{ImportJob: *job}
+	if job.TotalRows > 0 {
+		response.Progress = job.ProcessedRows * 100 / job.TotalRows
+	}
+	if job.ErrorReport != "" {
+		url, err := is.Store.PresignGet(catalogImportsBucket, job.ErrorReport, config.AppConfig.PresignExpiry)
+		if err != nil {
+			return nil, err
+		}
+		response.ErrorReportURL = url
+	}
+	return response, nil
+}
+
+// ProcessPendingImports claims and runs pending jobs, oldest first, until
+// none are left
+func (is *ImportService) ProcessPendingImports() error {
+	for {
+		var job models.ImportJob
+		err := is.DB.Where("status = ?", models.ImportStatusPending).Order("id").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Another worker may have claimed the job in the meantime
+		now := time.Now()
+		result := is.DB.Model(&models.ImportJob{}).
+			Where("id = ? AND status = ?", job.ID, models.ImportStatusPending).
+			Updates(map[string]interface{}{"status": models.ImportStatusProcessing, "started_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		job.Status = models.ImportStatusProcessing
+		job.StartedAt = &now
+
+		if err := is.runImport(&job); err != nil {
+			log.Printf("Import job %d failed: %v", job.ID, err)
+			is.finishImport(&job, models.ImportStatusFailed, err.Error())
+		}
+	}
+}
+
+// StartImportWorker fails jobs left behind by a previous run, then polls
+// for pending jobs every interval
+func (is *ImportService) StartImportWorker(interval time.Duration) {
+	is.DB.Model(&models.ImportJob{}).
+		Where("status = ? AND updated_at < ?", models.ImportStatusProcessing, time.Now().Add(-jobStaleAfter)).
+		Updates(map[string]interface{}{
+			"status":      models.ImportStatusFailed,
+			"error":       "import was interrupted, re-upload the rows that were not processed",
+			"finished_at": time.Now(),
+		})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := is.ProcessPendingImports(); err != nil {
+				log.Printf("Import worker failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Create one product per group of rows, recording row errors and progress
+// as it goes, then store the error report
+func (is *ImportService) runImport(job *models.ImportJob) error {
+	object, err := is.Store.Get(catalogImportsBucket, job.SourceObject)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(object)
+	object.Close()
+	if err != nil {
+		return err
+	}
+
+	rows, err := parseImportFile(job.Format, buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	schemas := make(map[uint]*models.CategorySchema)
+	var rowErrs []importRowError
+
+	for _, group := range groupImportRows(rows) {
+		updated, errs := is.importGroup(job.SellerID, group, schemas)
+		switch {
+		case len(errs) > 0:
+			job.FailedRows += len(group.Rows)
+			rowErrs = append(rowErrs, errs...)
+		case updated:
+			job.ProductsUpdated++
+		default:
+			job.ProductsCreated++
+		}
+		job.ProcessedRows += len(group.Rows)
+
+		is.DB.Model(job).Updates(map[string]interface{}{
+			"processed_rows":   job.ProcessedRows,
+			"failed_rows":      job.FailedRows,
+			"products_created": job.ProductsCreated,
+			"products_updated": job.ProductsUpdated,
+		})
+	}
+
+	if len(rowErrs) > 0 {
+		report, err := writeImportErrorReport(rowErrs)
+		if err != nil {
+			return err
+		}
+		objectName := strings.TrimSuffix(job.SourceObject, "source."+job.Format) + "errors.csv"
+		if _, err := is.Store.Put(catalogImportsBucket, objectName, bytes.NewReader(report), int64(len(report)), "text/csv"); err != nil {
+			return err
+		}
+		job.ErrorReport = objectName
+		is.DB.Model(job).Update("error_report", objectName)
+	}
+
+	is.finishImport(job, models.ImportStatusCompleted, "")
+	return nil
+}
+
+func (is *ImportService) finishImport(job *models.ImportJob, status int, message string) {
+	now := time.Now()
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+	is.DB.Model(job).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       message,
+		"finished_at": now,
+	})
+}
+
+// Validate and create the product of one group, or update the product
+// its SKU codes belong to. Any error fails the whole group, so a product
+// is never created or updated with only some of its SKUs.
+func (is *ImportService) importGroup(sellerID uint, group importGroup, schemas map[uint]*models.CategorySchema) (bool, []importRowError) {
+	if group.Key == "" {
+		errs := make([]importRowError, len(group.Rows))
+		for i, row := range group.Rows {
+			errs[i] = importRowError{Line: row.Line, Column: CatalogColumnParentKey, Message: "is required"}
+		}
+		return false, errs
+	}
+
+	var productID uint
+	var skuIDs map[string]uint
+	req, errs := is.buildImportRequest(group, schemas)
+	if len(errs) == 0 {
+		errs = importValidationErrors(group, binding.Validator.ValidateStruct(req))
+	}
+	if len(errs) == 0 {
+		productID, skuIDs, errs = is.findImportedProduct(sellerID, group, req)
+	}
+	if len(errs) == 0 {
+		var err error
+		if productID != 0 {
+			err = is.updateImportedProduct(sellerID, productID, req, skuIDs)
+		} else {
+			_, err = is.ProductService.CreateProduct(sellerID, req)
+		}
+		if err != nil {
+			errs = importCreateErrors(group, err)
+		}
+	}
+	if len(errs) == 0 {
+		return productID != 0, nil
+	}
+
+	// Rows without an error of their own were skipped with their product
+	failed := make(map[int]bool, len(errs))
+	for _, e := range errs {
+		failed[e.Line] = true
+	}
+	for _, row := range group.Rows {
+		if !failed[row.Line] {
+			errs = append(errs, importRowError{
+				Line:      row.Line,
+				ParentKey: group.Key,
+				Message:   "skipped because another row of this product is invalid",
+			})
+		}
+	}
+	return false, errs
+}
+
+// Find the product a group updates through its SKU codes, which are unique
+// per seller, so an exported file imported again updates the products it
+// came from. Returns a zero product ID when no code is known yet.
+func (is *ImportService) findImportedProduct(sellerID uint, group importGroup, req *CreateProductRequest) (uint, map[string]uint, []importRowError) {
+	var errs []importRowError
+	fail := func(row importRow, message string) {
+		errs = append(errs, importRowError{Line: row.Line, ParentKey: group.Key, Column: CatalogColumnSKUCode, Message: message})
+	}
+
+	codes := make([]string, 0, len(req.SKUs))
+	seen := make(map[string]bool, len(req.SKUs))
+	for i, sku := range req.SKUs {
+		code := strings.TrimSpace(sku.SKUCode)
+		if code == "" {
+			continue
+		}
+		if seen[code] {
+			fail(group.Rows[i], fmt.Sprintf("%q appears more than once in parent_key %q", code, group.Key))
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	if len(errs) > 0 || len(codes) == 0 {
+		return 0, nil, errs
+	}
+
+	var skus []models.SKU
+	err := is.DB.Select("id, product_id, sku_code").
+		Where("seller_id = ? AND sku_code IN ?", sellerID, codes).
+		Find(&skus).Error
+	if err != nil {
+		return 0, nil, []importRowError{{Line: group.Rows[0].Line, ParentKey: group.Key, Message: err.Error()}}
+	}
+	if len(skus) == 0 {
+		return 0, nil, nil
+	}
+
+	products := make(map[string]uint, len(skus))
+	skuIDs := make(map[string]uint, len(skus))
+	for _, sku := range skus {
+		products[sku.SKUCode] = sku.ProductID
+		skuIDs[sku.SKUCode] = sku.ID
+	}
+	var productID uint
+	for i, sku := range req.SKUs {
+		owner, ok := products[strings.TrimSpace(sku.SKUCode)]
+		if !ok {
+			continue
+		}
+		if productID == 0 {
+			productID = owner
+		} else if owner != productID {
+			fail(group.Rows[i], fmt.Sprintf("belongs to a different product than the other rows of parent_key %q", group.Key))
+		}
+	}
+	if len(errs) > 0 {
+		return 0, nil, errs
+	}
+	return productID, skuIDs, nil
+}
+
+// Update a product and its SKUs from a group in one transaction. Rows with
+// a known SKU code update that SKU, other rows add one; errors name the
+// SKU index like CreateProduct's.
+func (is *ImportService) updateImportedProduct(sellerID, productID uint, req *CreateProductRequest, skuIDs map[string]uint) error {
+	ps := is.ProductService
+	return is.DB.Transaction(func(tx *gorm.DB) error {
+		err := ps.updateProduct(tx, productID, sellerID, &UpdateProductRequest{
+			CategoryID:  &req.CategoryID,
+			Title:       &req.Title,
+			Description: &req.Description,
+			Brand:       &req.Brand,
+			Attributes:  &req.Attributes,
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range req.SKUs {
+			sku := &req.SKUs[i]
+			if skuID, ok := skuIDs[strings.TrimSpace(sku.SKUCode)]; ok {
+				_, err = ps.updateSKU(tx, productID, sellerID, skuID, &UpdateSKURequest{
+					Attributes: &sku.Attributes,
+					PriceMRP:   &sku.PriceMRP,
+					PriceSell:  &sku.PriceSell,
+					TaxPct:     &sku.TaxPct,
+					Barcode:    &sku.Barcode,
+				})
+			} else {
+				_, err = ps.addSKU(tx, productID, sellerID, sku)
+			}
+			if err != nil {
+				return prefixFieldErrors(err, fmt.Sprintf("skus[%d].", i))
+			}
+		}
+		return nil
+	})
+}
+
+// Move field errors under prefix, leaving other errors as they are
+func prefixFieldErrors(err error, prefix string) error {
+	var fieldErrs validation.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	prefixed := make(validation.FieldErrors, len(fieldErrs))
+	for field, message := range fieldErrs {
+		prefixed[prefix+field] = message
+	}
+	return prefixed
+}
+
+// Build the CreateProductRequest of a group, parsing numbers and typing
+// attribute values by the category schema
+func (is *ImportService) buildImportRequest(group importGroup, schemas map[uint]*models.CategorySchema) (*CreateProductRequest, []importRowError) {
+	var errs []importRowError
+	fail := func(row importRow, column, message string) {
+		errs = append(errs, importRowError{Line: row.Line, ParentKey: group.Key, Column: column, Message: message})
+	}
+
+	first := group.Rows[0]
+	req := &CreateProductRequest{
+		Title:       first.Cells[CatalogColumnTitle],
+		Description: first.Cells[CatalogColumnDescription],
+		Brand:       first.Cells[CatalogColumnBrand],
+	}
+	if value := first.Cells[CatalogColumnCategoryID]; value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			fail(first, CatalogColumnCategoryID, "must be a category id")
+		}
+		req.CategoryID = uint(id)
+	}
+	for _, row := range group.Rows[1:] {
+		for _, column := range productImportColumns {
+			if value := row.Cells[column]; value != "" && value != first.Cells[column] {
+				fail(row, column, fmt.Sprintf("differs from the first row of parent_key %q", group.Key))
+			}
+		}
+	}
+
+	schema := is.importSchema(req.CategoryID, schemas)
+	req.Attributes = importAttributes(first, CatalogAttributePrefix, schema)
+
+	for _, row := range group.Rows {
+		sku := CreateSKURequest{
+			SKUCode:    row.Cells[CatalogColumnSKUCode],
+			Barcode:    row.Cells[CatalogColumnBarcode],
+			Attributes: importAttributes(row, CatalogVariantPrefix, schema),
+		}
+		for column, target := range map[string]*decimal.Decimal{
+			CatalogColumnPriceMRP:  &sku.PriceMRP,
+			CatalogColumnPriceSell: &sku.PriceSell,
+			CatalogColumnTaxPct:    &sku.TaxPct,
+		} {
+			value := row.Cells[column]
+			if value == "" {
+				continue
+			}
+			amount, err := decimal.NewFromString(value)
+			if err != nil {
+				fail(row, column, "must be a number")
+				continue
+			}
+			*target = amount
+		}
+		req.SKUs = append(req.SKUs, sku)
+	}
+
+	return req, errs
+}
+
+// Schemas are cached per job; an unknown category gets an empty schema and
+// is reported by CreateProduct
+func (is *ImportService) importSchema(categoryID uint, schemas map[uint]*models.CategorySchema) *models.CategorySchema {
+	if schema, ok := schemas[categoryID]; ok {
+		return schema
+	}
+	schema := &models.CategorySchema{}
+	var category models.Category
+	if err := is.DB.First(&category, categoryID).Error; err == nil {
+		if parsed, err := category.GetSchema(); err == nil {
+			schema = parsed
+		}
+	}
+	schemas[categoryID] = schema
+	return schema
+}
+
+// Collect the attribute columns with the given prefix. Cells are text, so
+// number and boolean attributes are converted by their schema type; values
+// that do not convert are left as text for the validator to report.
+func importAttributes(row importRow, prefix string, schema *models.CategorySchema) models.Attributes {
+	types := make(map[string]string, len(schema.Attributes))
+	for _, def := range schema.Attributes {
+		types[def.Name] = def.Type
+	}
+
+	attrs := models.Attributes{}
+	for column, value := range row.Cells {
+		if !strings.HasPrefix(column, prefix) || value == "" {
+			continue
+		}
+		name := strings.TrimPrefix(column, prefix)
+		attrs[name] = value
+		switch types[name] {
+		case models.AttributeTypeNumber:
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				attrs[name] = n
+			}
+		case models.AttributeTypeBoolean:
+			if b, err := strconv.ParseBool(value); err == nil {
+				attrs[name] = b
+			}
+		}
+	}
+	return attrs
+}
+
+// Map binding rule failures back to the rows and columns they came from
+func importValidationErrors(group importGroup, err error) []importRowError {
+	if err == nil {
+		return nil
+	}
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []importRowError{{Line: group.Rows[0].Line, ParentKey: group.Key, Message: err.Error()}}
+	}
+
+	errs := make([]importRowError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		row := group.Rows[0]
+		if m := skuNamespacePattern.FindStringSubmatch(fe.Namespace()); m != nil {
+			if i, _ := strconv.Atoi(m[1]); i < len(group.Rows) {
+				row = group.Rows[i]
+			}
+		}
+		message := fmt.Sprintf("failed the %q rule", fe.Tag())
+		if fe.Param() != "" {
+			message = fmt.Sprintf("failed the %q rule (%s)", fe.Tag(), fe.Param())
+		}
+		errs = append(errs, importRowError{
+			Line:      row.Line,
+			ParentKey: group.Key,
+			Column:    importFieldColumns[fe.Field()],
+			Message:   message,
+		})
+	}
+	return errs
+}
+
+// Map create and update errors back to rows; field errors name the SKU
+// index
+func importCreateErrors(group importGroup, err error) []importRowError {
+	first := group.Rows[0]
+
+	var fieldErrs validation.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		column := ""
+		if errors.Is(err, ErrInvalidCategory) {
+			column = CatalogColumnCategoryID
+		}
+		return []importRowError{{Line: first.Line, ParentKey: group.Key, Column: column, Message: err.Error()}}
+	}
+
+	errs := make([]importRowError, 0, len(fieldErrs))
+	for field, message := range fieldErrs {
+		row, column := first, field
+		if m := skuFieldPattern.FindStringSubmatch(field); m != nil {
+			if i, _ := strconv.Atoi(m[1]); i < len(group.Rows) {
+				row = group.Rows[i]
+			}
+			column = strings.Replace(m[2], "attributes.", CatalogVariantPrefix, 1)
+		} else {
+			column = strings.Replace(column, "attributes.", CatalogAttributePrefix, 1)
+		}
+		errs = append(errs, importRowError{Line: row.Line, ParentKey: group.Key, Column: column, Message: message})
+	}
+	return errs
+}
+
+// Read a catalog file into rows keyed by column, checking the header
+func parseImportFile(format string, data []byte) ([]importRow, error) {
+	records, err := spreadsheet.Read(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	header := make([]string, len(records[0].Cells))
+	seen := make(map[string]bool, len(header))
+	var unknown []string
+	for i, name := range records[0].Cells {
+		name = strings.ToLower(strings.TrimSpace(name))
+		header[i] = name
+		if name == "" {
+			continue
+		}
+		if !isCatalogColumn(name) || seen[name] {
+			unknown = append(unknown, name)
+		}
+		seen[name] = true
+	}
+	var missing []string
+	for _, column := range requiredImportColumns {
+		if !seen[column] {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 || len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: missing [%s], unknown or repeated [%s]",
+			ErrImportColumns, strings.Join(missing, ", "), strings.Join(unknown, ", "))
+	}
+
+	var rows []importRow
+	for _, record := range records[1:] {
+		cells := make(map[string]string, len(header))
+		blank := true
+		for j, value := range record.Cells {
+			if j >= len(header) || header[j] == "" || isReadOnlyColumn(header[j]) {
+				continue
+			}
+			value = unescapeCatalogCell(strings.TrimSpace(value))
+			cells[header[j]] = value
+			blank = blank && value == ""
+		}
+		// Rows with values only in ignored columns count as blank
+		if !blank {
+			rows = append(rows, importRow{Line: record.Line, Cells: cells})
+		}
+	}
+	return rows, nil
+}
+
+// Group rows by parent key in order of first appearance
+func groupImportRows(rows []importRow) []importGroup {
+	var groups []importGroup
+	index := make(map[string]int)
+	for _, row := range rows {
+		key := row.Cells[CatalogColumnParentKey]
+		if key == "" {
+			// Rows without a key cannot be grouped and fail on their own
+			groups = append(groups, importGroup{Rows: []importRow{row}})
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, importGroup{Key: key})
+		}
+		groups[i].Rows = append(groups[i].Rows, row)
+	}
+	return groups
+}
+
+func writeImportErrorReport(errs []importRowError) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"row", "parent_key", "column", "error"})
+	for _, e := range errs {
+		writer.Write([]string{strconv.Itoa(e.Line), e.ParentKey, e.Column, e.Message})
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// Request DTOs
+type ImportFilters struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=20"`
+}
+
+// Response DTOs
+type ImportJobResponse struct {
+	models.ImportJob
+	Progress       int    `json:"progress"` // Percentage of rows processed
+	ErrorReportURL string `json:"error_report_url,omitempty"`
+}
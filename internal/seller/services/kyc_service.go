@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+const (
+	kycBucket             = "kyc-documents"
+	sellerDocumentsBucket = "seller-documents"
+)
+
+var (
+	ErrInvalidKYCType = errors.New("unsupported KYC document type")
+	ErrKYCTooLarge    = errors.New("KYC document exceeds the maximum upload size")
+	ErrKYCEmpty       = errors.New("KYC document is empty")
+	ErrKYCContentType = errors.New("KYC document must be a PDF, JPEG or PNG")
+)
+
+// Content types accepted for KYC documents, detected from the file bytes
+var kycContentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type KYCService struct {
+	DB    *gorm.DB
+	Store storage.ObjectStore
+}
+
+func NewKYCService() *KYCService {
+	return &KYCService{
+		DB:    db.GetDB(),
+		Store: storage.GetStore(),
+	}
+}
+
+// Upload a KYC document. A new upload supersedes any pending document of
+// the same type, so each seller has at most one awaiting review per type.
+// An approved document stays in force until its replacement is approved.
+func (ks *KYCService) UploadDocument(sellerID uint, docType string, file io.Reader, size int64) (*models.KYC, error) {
+	return ks.uploadDocument(ks.DB, sellerID, docType, file, size)
+}
+
+// uploadDocument creates the document row through tx, so callers can make
+// it part of their own transaction
+func (ks *KYCService) uploadDocument(tx *gorm.DB, sellerID uint, docType string, file io.Reader, size int64) (*models.KYC, error) {
+	if !models.IsValidKYCType(docType) {
+		return nil, ErrInvalidKYCType
+	}
+	if size <= 0 {
+		return nil, ErrKYCEmpty
+	}
+	if size > config.AppConfig.KYCMaxUploadBytes {
+		return nil, ErrKYCTooLarge
+	}
+
+	// Sniff the real content type instead of trusting the client header
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	ext, ok := kycContentTypes[contentType]
+	if !ok {
+		return nil, ErrKYCContentType
+	}
+
+	bucket := kycBucket
+	if models.IsBusinessDocument(docType) {
+		bucket = sellerDocumentsBucket
+	}
+
+	objectName := fmt.Sprintf("sellers/%d/%s/%d%s", sellerID, docType, time.Now().UnixNano(), ext)
+	reader := io.MultiReader(bytes.NewReader(head), file)
+	path, err := ks.Store.Put(bucket, objectName, reader, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &models.KYC{
+		SellerID:    sellerID,
+		Type:        docType,
+		DocumentURL: path,
+		ContentType: contentType,
+		Size:        size,
+		Status:      models.KYCStatusPending,
+	}
+
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.KYC{}).
+			Where("seller_id = ? AND type = ? AND status = ?", sellerID, docType, models.KYCStatusPending).
+			Update("status", models.KYCStatusSuperseded).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(document).Error
+	})
+	if err != nil {
+		ks.Store.Delete(bucket, objectName)
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// List the seller's KYC documents with short-lived download links
+func (ks *KYCService) ListDocuments(sellerID uint) ([]KYCDocumentResponse, error) {
+	var documents []models.KYC
+	err := ks.DB.
+		Where("seller_id = ? AND status <> ?", sellerID, models.KYCStatusSuperseded).
+		Order("created_at DESC").
+		Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]KYCDocumentResponse, 0, len(documents))
+	for _, document := range documents {
+		response, err := NewKYCDocumentResponse(ks.Store, &document)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *response)
+	}
+
+	return result, nil
+}
+
+// Response DTOs
+type KYCDocumentResponse struct {
+	models.KYC
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// NewKYCDocumentResponse wraps a KYC row with a presigned download URL so
+// clients never receive a permanent link to the document
+func NewKYCDocumentResponse(store storage.ObjectStore, document *models.KYC) (*KYCDocumentResponse, error) {
+	bucket, object, err := storage.ParseObjectPath(document.DocumentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := config.AppConfig.PresignExpiry
+	url, err := store.PresignGet(bucket, object, expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KYCDocumentResponse{
+		KYC:         *document,
+		DownloadURL: url,
+		ExpiresAt:   time.Now().Add(expiry),
+	}, nil
+}
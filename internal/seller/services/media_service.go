@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/imaging"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+const productImagesBucket = "product-images"
+
+var (
+	ErrMediaNotFound     = errors.New("media not found")
+	ErrMediaContentType  = errors.New("media must be a JPEG, PNG, WebP or GIF image or an MP4 or WebM video")
+	ErrMediaTooLarge     = errors.New("media exceeds the maximum upload size")
+	ErrMediaEmpty        = errors.New("media file is empty")
+	ErrMediaOrderInvalid = errors.New("media order must list every media item of the product exactly once")
+)
+
+// Content types accepted for product media, detected from the file bytes
+var mediaContentTypes = map[string]struct {
+	Type string
+	Ext  string
+}{
+	"image/jpeg": {models.MediaTypeImage, ".jpg"},
+	"image/png":  {models.MediaTypeImage, ".png"},
+	"image/webp": {models.MediaTypeImage, ".webp"},
+	"image/gif":  {models.MediaTypeImage, ".gif"},
+	"video/mp4":  {models.MediaTypeVideo, ".mp4"},
+	"video/webm": {models.MediaTypeVideo, ".webm"},
+}
+
+type MediaService struct {
+	DB             *gorm.DB
+	Store          storage.ObjectStore
+	ProductService *ProductService
+}
+
+func NewMediaService() *MediaService {
+	return &MediaService{
+		DB:             db.GetDB(),
+		Store:          storage.GetStore(),
+		ProductService: NewProductService(),
+	}
+}
+
+// List product media in display order
+func (ms *MediaService) ListMedia(productID uint) ([]models.Media, error) {
+	var media []models.Media
+
+	err := ms.DB.
+		Where("entity_type = ? AND entity_id = ?", models.MediaEntityProduct, productID).
+		Order("sort ASC, id ASC").
+		Find(&media).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range media {
+		if err := storage.ResolveMediaLinks(ms.Store, &media[i], config.AppConfig.MediaURLExpiry); err != nil {
+			return nil, err
+		}
+	}
+	return media, nil
+}
+
+// Upload an image or video for a product and append it to the gallery
+func (ms *MediaService) UploadMedia(productID uint, file io.Reader, size int64, altText string) (*models.Media, error) {
+	return ms.uploadMedia(ms.DB, productID, file, size, altText)
+}
+
+// uploadMedia creates the media row through tx, so callers can make it
+// part of their own transaction
+func (ms *MediaService) uploadMedia(tx *gorm.DB, productID uint, file io.Reader, size int64, altText string) (*models.Media, error) {
+	if size <= 0 {
+		return nil, ErrMediaEmpty
+	}
+
+	// Sniff the real content type instead of trusting the client header
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	kind, ok := mediaContentTypes[contentType]
+	if !ok {
+		return nil, ErrMediaContentType
+	}
+
+	maxSize := config.AppConfig.MediaMaxImageBytes
+	if kind.Type == models.MediaTypeVideo {
+		maxSize = config.AppConfig.MediaMaxVideoBytes
+	}
+	if size > maxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	reader := io.MultiReader(bytes.NewReader(head), file)
+	baseName := fmt.Sprintf("products/%d/%d", productID, time.Now().UnixNano())
+
+	media := &models.Media{
+		EntityType: models.MediaEntityProduct,
+		EntityID:   productID,
+		Type:       kind.Type,
+		AltText:    altText,
+	}
+
+	var uploaded []string
+	if kind.Type == models.MediaTypeImage {
+		uploaded, err = ms.storeImage(media, baseName, kind.Ext, contentType, reader, size)
+	} else {
+		uploaded, err = ms.storeVideo(media, baseName, kind.Ext, contentType, reader, size)
+	}
+	if err != nil {
+		ms.deleteObjects(uploaded)
+		return nil, err
+	}
+
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		var maxSort int
+		tx.Model(&models.Media{}).
+			Where("entity_type = ? AND entity_id = ?", models.MediaEntityProduct, productID).
+			Select("COALESCE(MAX(sort), -1)").
+			Scan(&maxSort)
+		media.Sort = maxSort + 1
+
+		if err := tx.Create(media).Error; err != nil {
+			return err
+		}
+
+		_, err := ms.ProductService.RecalculateScore(tx, productID)
+		return err
+	})
+	if err != nil {
+		ms.deleteObjects(uploaded)
+		return nil, err
+	}
+
+	return media, storage.ResolveMediaLinks(ms.Store, media, config.AppConfig.MediaURLExpiry)
+}
+
+// Validate an image, then store the original with its resized and WebP
+// renditions. Returns the paths of every stored object.
+func (ms *MediaService) storeImage(media *models.Media, baseName, ext, contentType string, reader io.Reader, size int64) ([]string, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, size))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := imaging.Process(data, imaging.Rules{
+		MinWidth:       config.AppConfig.ImageMinWidth,
+		MinHeight:      config.AppConfig.ImageMinHeight,
+		MaxPixels:      config.AppConfig.ImageMaxPixels,
+		MaxAspectRatio: config.AppConfig.ImageMaxAspectRatio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var uploaded []string
+	path, err := ms.Store.Put(productImagesBucket, baseName+ext, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		return uploaded, err
+	}
+	uploaded = append(uploaded, path)
+
+	variants := make(map[string]string, len(result.Renditions))
+	for _, rendition := range result.Renditions {
+		objectName := fmt.Sprintf("%s_%s%s", baseName, rendition.Name, rendition.Ext)
+		variantPath, err := ms.Store.Put(productImagesBucket, objectName,
+			bytes.NewReader(rendition.Data), int64(len(rendition.Data)), rendition.ContentType)
+		if err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, variantPath)
+		variants[rendition.Name] = variantPath
+	}
+
+	media.Path = path
+	media.Width = result.Width
+	media.Height = result.Height
+	return uploaded, media.SetVariantPaths(variants)
+}
+
+// Store a video as-is
+func (ms *MediaService) storeVideo(media *models.Media, baseName, ext, contentType string, reader io.Reader, size int64) ([]string, error) {
+	path, err := ms.Store.Put(productImagesBucket, baseName+ext, reader, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	media.Path = path
+	return []string{path}, nil
+}
+
+// Best-effort removal of stored objects
+func (ms *MediaService) deleteObjects(paths []string) {
+	for _, path := range paths {
+		if bucket, object, err := storage.ParseObjectPath(path); err == nil {
+			ms.Store.Delete(bucket, object)
+		}
+	}
+}
+
+// Update media alt text
+func (ms *MediaService) UpdateAltText(productID, mediaID uint, altText string) (*models.Media, error) {
+	media, err := ms.findMedia(ms.DB, productID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ms.DB.Model(media).Update("alt_text", altText).Error; err != nil {
+		return nil, err
+	}
+
+	return media, storage.ResolveMediaLinks(ms.Store, media, config.AppConfig.MediaURLExpiry)
+}
+
+// Reorder the product gallery. mediaIDs must contain every media item of
+// the product; position in the slice becomes Media.Sort.
+func (ms *MediaService) ReorderMedia(productID uint, mediaIDs []uint) ([]models.Media, error) {
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&models.Media{}).
+			Where("entity_type = ? AND entity_id = ?", models.MediaEntityProduct, productID).
+			Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+
+		if len(existing) != len(mediaIDs) {
+			return ErrMediaOrderInvalid
+		}
+		known := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			known[id] = true
+		}
+		for _, id := range mediaIDs {
+			if !known[id] {
+				return ErrMediaOrderInvalid
+			}
+			delete(known, id)
+		}
+
+		for sort, id := range mediaIDs {
+			if err := tx.Model(&models.Media{}).Where("id = ?", id).Update("sort", sort).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.ListMedia(productID)
+}
+
+// Delete a media item and its stored object
+func (ms *MediaService) DeleteMedia(productID, mediaID uint) error {
+	var media *models.Media
+
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		media, err = ms.findMedia(tx, productID, mediaID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(media).Error; err != nil {
+			return err
+		}
+
+		_, err = ms.ProductService.RecalculateScore(tx, productID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// The row is gone, so a failed object delete only leaves an orphan file
+	paths := []string{media.Path}
+	if variants, err := media.GetVariantPaths(); err == nil {
+		for _, path := range variants {
+			paths = append(paths, path)
+		}
+	}
+	ms.deleteObjects(paths)
+
+	return nil
+}
+
+func (ms *MediaService) findMedia(tx *gorm.DB, productID, mediaID uint) (*models.Media, error) {
+	var media models.Media
+	err := tx.
+		Where("id = ? AND entity_type = ? AND entity_id = ?", mediaID, models.MediaEntityProduct, productID).
+		First(&media).Error
+	if err != nil {
+		return nil, ErrMediaNotFound
+	}
+	return &media, nil
+}
+
+// Request DTOs
+type UpdateMediaRequest struct {
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+type ReorderMediaRequest struct {
+	MediaIDs []uint `json:"media_ids" binding:"required,min=1"`
+}
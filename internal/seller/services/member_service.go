@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+var (
+	ErrMemberNotFound = errors.New("member not found")
+	ErrUserNotFound   = errors.New("no user registered with this email")
+	ErrAlreadyMember  = errors.New("user is already a member of this seller")
+	ErrInvalidRole    = errors.New("invalid seller role")
+	ErrLastOwner      = errors.New("seller must keep at least one active owner")
+)
+
+type MemberService struct {
+	DB *gorm.DB
+}
+
+func NewMemberService() *MemberService {
+	return &MemberService{
+		DB: db.GetDB(),
+	}
+}
+
+// List seller team members
+func (ms *MemberService) ListMembers(sellerID uint) ([]MemberResponse, error) {
+	var members []MemberResponse
+
+	err := ms.DB.
+		Table("seller_users").
+		Select("seller_users.id, seller_users.user_id, users.name, users.email, seller_users.role, seller_users.status").
+		Joins("JOIN users ON users.id = seller_users.user_id").
+		Where("seller_users.seller_id = ?", sellerID).
+		Order("seller_users.id ASC").
+		Scan(&members).Error
+
+	return members, err
+}
+
+// Invite a registered user to the seller team
+func (ms *MemberService) InviteMember(sellerID uint, req *InviteMemberRequest) (*models.SellerUser, error) {
+	if !models.IsValidSellerRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	var user models.User
+	if err := ms.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var count int64
+	ms.DB.Model(&models.SellerUser{}).
+		Where("seller_id = ? AND user_id = ?", sellerID, user.ID).
+		Count(&count)
+	if count > 0 {
+		return nil, ErrAlreadyMember
+	}
+
+	member := &models.SellerUser{
+		SellerID: sellerID,
+		UserID:   user.ID,
+		Role:     req.Role,
+		Status:   models.SellerUserStatusInvited,
+	}
+	if err := ms.DB.Create(member).Error; err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Accept a pending invitation for the given user
+func (ms *MemberService) AcceptInvitation(sellerID, userID uint) (*models.SellerUser, error) {
+	var member models.SellerUser
+	err := ms.DB.
+		Where("seller_id = ? AND user_id = ? AND status = ?", sellerID, userID, models.SellerUserStatusInvited).
+		First(&member).Error
+	if err != nil {
+		return nil, ErrMemberNotFound
+	}
+
+	if err := ms.DB.Model(&member).Update("status", models.SellerUserStatusActive).Error; err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Change the role of a team member
+func (ms *MemberService) ChangeRole(sellerID, memberID uint, role string) (*models.SellerUser, error) {
+	if !models.IsValidSellerRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	var member *models.SellerUser
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		member, err = ms.findMember(tx, sellerID, memberID)
+		if err != nil {
+			return err
+		}
+
+		if member.Role == models.SellerRoleOwner && role != models.SellerRoleOwner {
+			if err := ms.ensureAnotherOwner(tx, sellerID, member.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(member).Update("role", role).Error
+	})
+
+	return member, err
+}
+
+// Suspend a team member, revoking their access without removing them
+func (ms *MemberService) SuspendMember(sellerID, memberID uint) (*models.SellerUser, error) {
+	var member *models.SellerUser
+	err := ms.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		member, err = ms.findMember(tx, sellerID, memberID)
+		if err != nil {
+			return err
+		}
+
+		if member.Role == models.SellerRoleOwner {
+			if err := ms.ensureAnotherOwner(tx, sellerID, member.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(member).Update("status", models.SellerUserStatusSuspended).Error
+	})
+
+	return member, err
+}
+
+// Remove a team member
+func (ms *MemberService) RemoveMember(sellerID, memberID uint) error {
+	return ms.DB.Transaction(func(tx *gorm.DB) error {
+		member, err := ms.findMember(tx, sellerID, memberID)
+		if err != nil {
+			return err
+		}
+
+		if member.Role == models.SellerRoleOwner {
+			if err := ms.ensureAnotherOwner(tx, sellerID, member.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(member).Error
+	})
+}
+
+func (ms *MemberService) findMember(tx *gorm.DB, sellerID, memberID uint) (*models.SellerUser, error) {
+	var member models.SellerUser
+	if err := tx.Where("id = ? AND seller_id = ?", memberID, sellerID).First(&member).Error; err != nil {
+		return nil, ErrMemberNotFound
+	}
+	return &member, nil
+}
+
+// Make sure the seller has an active owner other than the given member
+func (ms *MemberService) ensureAnotherOwner(tx *gorm.DB, sellerID, memberID uint) error {
+	var count int64
+	tx.Model(&models.SellerUser{}).
+		Where("seller_id = ? AND role = ? AND status = ? AND id <> ?",
+			sellerID, models.SellerRoleOwner, models.SellerUserStatusActive, memberID).
+		Count(&count)
+
+	if count == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// Request DTOs
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Response DTOs
+type MemberResponse struct {
+	ID     uint   `json:"id"`
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	Status int    `json:"status"`
+}
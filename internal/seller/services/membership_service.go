@@ -0,0 +1,38 @@
+package services
+
+import (
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+)
+
+type MembershipService struct {
+	DB *gorm.DB
+}
+
+func NewMembershipService() *MembershipService {
+	return &MembershipService{
+		DB: db.GetDB(),
+	}
+}
+
+// Get the active membership of a user in a seller
+func (ms *MembershipService) GetActiveMembership(sellerID, userID uint) (*models.SellerUser, error) {
+	var membership models.SellerUser
+
+	err := ms.DB.
+		Where("seller_id = ? AND user_id = ? AND status = ?", sellerID, userID, models.SellerUserStatusActive).
+		First(&membership).Error
+
+	return &membership, err
+}
+
+// Get the seller a product belongs to
+func (ms *MembershipService) GetProductSellerID(productID uint) (uint, error) {
+	var product models.Product
+
+	err := ms.DB.Select("id", "seller_id").First(&product, productID).Error
+
+	return product.SellerID, err
+}
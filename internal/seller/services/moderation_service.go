@@ -0,0 +1,186 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/models"
+	"gocom/main/internal/search"
+)
+
+// ModerationService owns product status transitions so that every one of
+// them, from seller submissions to admin decisions, is recorded
+type ModerationService struct {
+	DB *gorm.DB
+}
+
+func NewModerationService() *ModerationService {
+	return &ModerationService{
+		DB: db.GetDB(),
+	}
+}
+
+// Submit a product for review. Products failing the automatic checks are
+// rejected straight away, the rest wait in the moderation queue.
+func (ms *ModerationService) Submit(tx *gorm.DB, product *models.Product, action string, actorID *uint) error {
+	reasons, err := ms.RunAutoChecks(tx, product)
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return ms.Transition(tx, product, models.ProductStatusRejected, models.ModerationActionAutoReject, nil, reasons, "")
+	}
+
+	return ms.Transition(tx, product, models.ProductStatusPendingReview, action, actorID, nil, "")
+}
+
+// Transition moves a product to a new status and records the move.
+// Rejections store their reasons on the product for the seller to see.
+func (ms *ModerationService) Transition(tx *gorm.DB, product *models.Product, status int, action string, actorID *uint, reasons []models.RejectionReason, note string) error {
+	var reasonsJSON json.RawMessage
+	if len(reasons) > 0 {
+		data, err := json.Marshal(reasons)
+		if err != nil {
+			return err
+		}
+		reasonsJSON = data
+	}
+
+	updates := map[string]interface{}{"status": status}
+	switch status {
+	case models.ProductStatusRejected:
+		updates["rejection"] = reasonsJSON
+		updates["edited_since_rejection"] = false
+	case models.ProductStatusPendingReview, models.ProductStatusPublished:
+		updates["rejection"] = nil
+	}
+
+	from := product.Status
+	if err := tx.Model(product).Updates(updates).Error; err != nil {
+		return err
+	}
+	product.Status = status
+	if status == models.ProductStatusRejected {
+		product.Rejection = reasonsJSON
+		product.EditedSinceRejection = false
+	}
+
+	// Products enter and leave the marketplace search here
+	switch {
+	case status == models.ProductStatusPublished && from != models.ProductStatusPublished:
+		if err := search.RecordEvent(tx, product.ID, models.SearchEventPublish); err != nil {
+			return err
+		}
+	case from == models.ProductStatusPublished && status != models.ProductStatusPublished:
+		if err := search.RecordEvent(tx, product.ID, models.SearchEventUnpublish); err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(&models.ProductModeration{
+		ProductID:  product.ID,
+		FromStatus: from,
+		ToStatus:   status,
+		Action:     action,
+		Reasons:    reasonsJSON,
+		Note:       note,
+		ActorID:    actorID,
+	}).Error
+}
+
+// Moderation history of a product, oldest first
+func (ms *ModerationService) History(productID uint) ([]models.ProductModeration, error) {
+	var history []models.ProductModeration
+	err := ms.DB.Where("product_id = ?", productID).Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
+
+// RunAutoChecks looks for prohibited keywords in the product content and
+// for restricted brands the seller is not authorised to sell
+func (ms *ModerationService) RunAutoChecks(tx *gorm.DB, product *models.Product) ([]models.RejectionReason, error) {
+	var reasons []models.RejectionReason
+
+	fields, err := ms.moderatedFields(tx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	var keywords []string
+	if err := tx.Model(&models.ProhibitedKeyword{}).Pluck("keyword", &keywords).Error; err != nil {
+		return nil, err
+	}
+	prohibited := search.NewKeywordMatcher(keywords)
+	for _, field := range fields {
+		for _, keyword := range prohibited.Match(field.Text) {
+			reasons = append(reasons, models.RejectionReason{
+				Code:    models.RejectionProhibitedKeyword,
+				Field:   field.Name,
+				Message: fmt.Sprintf("%q is not allowed on the marketplace", keyword),
+			})
+		}
+	}
+
+	if brand := strings.ToLower(strings.TrimSpace(product.Brand)); brand != "" {
+		var restricted models.RestrictedBrand
+		err := tx.Where("brand = ?", brand).First(&restricted).Error
+		if err == nil {
+			var count int64
+			tx.Model(&models.BrandAuthorization{}).
+				Where("restricted_brand_id = ? AND seller_id = ?", restricted.ID, product.SellerID).
+				Count(&count)
+			if count == 0 {
+				reasons = append(reasons, models.RejectionReason{
+					Code:    models.RejectionRestrictedBrand,
+					Field:   "brand",
+					Message: fmt.Sprintf("Selling %q requires brand authorization", product.Brand),
+				})
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	return reasons, nil
+}
+
+type moderatedField struct {
+	Name string
+	Text string
+}
+
+// Text fields of a product and its SKUs that keyword checks apply to
+func (ms *ModerationService) moderatedFields(tx *gorm.DB, product *models.Product) ([]moderatedField, error) {
+	fields := []moderatedField{
+		{"title", product.Title},
+		{"description", product.Description},
+		{"brand", product.Brand},
+	}
+
+	attrs, err := product.GetAttributes()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(attrs) {
+		fields = append(fields, moderatedField{"attributes." + key, attrs.String(key)})
+	}
+
+	var skus []models.SKU
+	if err := tx.Where("product_id = ? AND is_active = ?", product.ID, true).Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	for _, sku := range skus {
+		skuAttrs, err := sku.GetAttributes()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(skuAttrs) {
+			fields = append(fields, moderatedField{fmt.Sprintf("skus[%s].attributes.%s", sku.SKUCode, key), skuAttrs.String(key)})
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/models"
+)
+
+var (
+	ErrSellerNotFound       = errors.New("seller not found")
+	ErrGSTINTaken           = errors.New("GSTIN is already registered to another seller")
+	ErrSellerLocked         = errors.New("legal details of an approved seller cannot be changed")
+	ErrNotResubmittable     = errors.New("only rejected sellers can be resubmitted")
+	ErrOnboardingIncomplete = errors.New("onboarding checklist is incomplete")
+)
+
+// KYC documents that must be approved before a seller can be approved
+var requiredKYCDocuments = []struct {
+	Type  string
+	Label string
+}{
+	{models.KYCTypePAN, "Verified PAN card"},
+	{models.KYCTypeGSTIN, "Verified GST registration certificate"},
+	{models.KYCTypeCancelledCheque, "Verified cancelled cheque"},
+}
+
+type OnboardingService struct {
+	DB *gorm.DB
+}
+
+func NewOnboardingService() *OnboardingService {
+	return &OnboardingService{
+		DB: db.GetDB(),
+	}
+}
+
+// Register a new seller and make the user its owner
+func (ons *OnboardingService) RegisterSeller(userID uint, req *RegisterSellerRequest) (*models.Seller, error) {
+	gstin := validation.NormalizeTaxID(req.GSTIN)
+	pan := validation.NormalizeTaxID(req.PAN)
+
+	if err := validation.ValidateGSTINWithPAN(gstin, pan); err != nil {
+		return nil, err
+	}
+	if err := ons.ensureGSTINAvailable(gstin, 0); err != nil {
+		return nil, err
+	}
+
+	seller := &models.Seller{
+		LegalName:   strings.TrimSpace(req.LegalName),
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		GSTIN:       gstin,
+		PAN:         pan,
+		Status:      models.SellerStatusPending,
+	}
+
+	err := ons.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(seller).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.SellerUser{
+			SellerID: seller.ID,
+			UserID:   userID,
+			Role:     models.SellerRoleOwner,
+			Status:   models.SellerUserStatusActive,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return seller, nil
+}
+
+// Update seller profile. Legal identifiers are frozen once approved.
+func (ons *OnboardingService) UpdateSeller(sellerID uint, req *UpdateSellerRequest) (*models.Seller, error) {
+	seller, err := ons.getSeller(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.DisplayName != nil {
+		updates["display_name"] = strings.TrimSpace(*req.DisplayName)
+	}
+
+	if req.LegalName != nil || req.GSTIN != nil || req.PAN != nil {
+		if seller.Status == models.SellerStatusApproved {
+			return nil, ErrSellerLocked
+		}
+
+		gstin, pan := seller.GSTIN, seller.PAN
+		if req.GSTIN != nil {
+			gstin = validation.NormalizeTaxID(*req.GSTIN)
+		}
+		if req.PAN != nil {
+			pan = validation.NormalizeTaxID(*req.PAN)
+		}
+		if err := validation.ValidateGSTINWithPAN(gstin, pan); err != nil {
+			return nil, err
+		}
+		if err := ons.ensureGSTINAvailable(gstin, seller.ID); err != nil {
+			return nil, err
+		}
+
+		updates["gstin"] = gstin
+		updates["pan"] = pan
+		if req.LegalName != nil {
+			updates["legal_name"] = strings.TrimSpace(*req.LegalName)
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := ons.DB.Model(seller).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return ons.getSeller(sellerID)
+}
+
+// Update the seller's payout bank reference
+func (ons *OnboardingService) UpdateBankDetails(sellerID uint, bankRef string) (*models.Seller, error) {
+	seller, err := ons.getSeller(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ons.DB.Model(seller).Update("bank_ref", strings.TrimSpace(bankRef)).Error; err != nil {
+		return nil, err
+	}
+
+	return seller, nil
+}
+
+// Resubmit a rejected seller for review after fixing the remarks
+func (ons *OnboardingService) Resubmit(sellerID uint) (*models.Seller, error) {
+	seller, err := ons.getSeller(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if seller.Status != models.SellerStatusRejected {
+		return nil, ErrNotResubmittable
+	}
+
+	checklist, err := ons.GetChecklist(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if !checklist.Complete {
+		return nil, ErrOnboardingIncomplete
+	}
+
+	if err := ons.DB.Model(seller).Update("status", models.SellerStatusPending).Error; err != nil {
+		return nil, err
+	}
+
+	return seller, nil
+}
+
+// Get the onboarding checklist showing what is still missing
+func (ons *OnboardingService) GetChecklist(sellerID uint) (*OnboardingChecklist, error) {
+	seller, err := ons.getSeller(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []ChecklistItem{
+		{
+			Key:      "legal_name",
+			Label:    "Registered legal name",
+			Complete: seller.LegalName != "",
+		},
+		{
+			Key:      "display_name",
+			Label:    "Storefront display name",
+			Complete: seller.DisplayName != "",
+		},
+		{
+			Key:      "pan",
+			Label:    "Valid PAN",
+			Complete: validation.ValidatePAN(seller.PAN) == nil,
+		},
+		{
+			Key:      "gstin",
+			Label:    "Valid GSTIN issued against the PAN",
+			Complete: validation.ValidateGSTINWithPAN(seller.GSTIN, seller.PAN) == nil,
+		},
+		{
+			Key:      "bank_account",
+			Label:    "Payout bank account",
+			Complete: seller.BankRef != "",
+		},
+	}
+
+	// Verified KYC documents
+	var approvedTypes []string
+	if err := ons.DB.Model(&models.KYC{}).
+		Where("seller_id = ? AND status = ?", sellerID, models.KYCStatusApproved).
+		Distinct().
+		Pluck("type", &approvedTypes).Error; err != nil {
+		return nil, err
+	}
+	for _, doc := range requiredKYCDocuments {
+		items = append(items, ChecklistItem{
+			Key:      "kyc_" + strings.ToLower(doc.Type),
+			Label:    doc.Label,
+			Complete: slices.Contains(approvedTypes, doc.Type),
+		})
+	}
+
+	checklist := &OnboardingChecklist{
+		SellerID: seller.ID,
+		Status:   seller.Status,
+		Remarks:  seller.StatusRemarks,
+		Items:    items,
+		Complete: true,
+	}
+	for _, item := range items {
+		if !item.Complete {
+			checklist.Complete = false
+			checklist.Missing = append(checklist.Missing, item.Key)
+		}
+	}
+
+	return checklist, nil
+}
+
+func (ons *OnboardingService) getSeller(sellerID uint) (*models.Seller, error) {
+	var seller models.Seller
+	if err := ons.DB.First(&seller, sellerID).Error; err != nil {
+		return nil, ErrSellerNotFound
+	}
+	return &seller, nil
+}
+
+// Make sure no other live seller uses the GSTIN
+func (ons *OnboardingService) ensureGSTINAvailable(gstin string, excludeSellerID uint) error {
+	var count int64
+	ons.DB.Model(&models.Seller{}).
+		Where("gstin = ? AND id <> ? AND status <> ?", gstin, excludeSellerID, models.SellerStatusRejected).
+		Count(&count)
+
+	if count > 0 {
+		return ErrGSTINTaken
+	}
+	return nil
+}
+
+// Request DTOs
+type RegisterSellerRequest struct {
+	LegalName   string `json:"legal_name" binding:"required,min=2,max=200"`
+	DisplayName string `json:"display_name" binding:"required,min=2,max=100"`
+	GSTIN       string `json:"gstin" binding:"required"`
+	PAN         string `json:"pan" binding:"required"`
+}
+
+type UpdateSellerRequest struct {
+	LegalName   *string `json:"legal_name" binding:"omitempty,min=2,max=200"`
+	DisplayName *string `json:"display_name" binding:"omitempty,min=2,max=100"`
+	GSTIN       *string `json:"gstin"`
+	PAN         *string `json:"pan"`
+}
+
+type UpdateBankRequest struct {
+	BankRef string `json:"bank_ref" binding:"required"`
+}
+
+// Response DTOs
+type ChecklistItem struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Complete bool   `json:"complete"`
+}
+
+type OnboardingChecklist struct {
+	SellerID uint            `json:"seller_id"`
+	Status   int             `json:"status"`
+	Remarks  string          `json:"remarks,omitempty"`
+	Complete bool            `json:"complete"`
+	Missing  []string        `json:"missing,omitempty"`
+	Items    []ChecklistItem `json:"items"`
+}
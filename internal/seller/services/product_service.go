@@ -1,23 +1,47 @@
 package services
 
 import (
+    "bytes"
+    "encoding/json"
     "errors"
     "fmt"
-    "strings"
     "gorm.io/gorm"
     "github.com/shopspring/decimal"
     
     "gocom/main/internal/models"
+    "gocom/main/internal/common/config"
     "gocom/main/internal/common/db"
+    "gocom/main/internal/common/validation"
+    "gocom/main/internal/integrations/storage"
+    "gocom/main/internal/search"
+)
+
+var (
+    ErrProductNotFound    = errors.New("product not found")
+    ErrSKUNotFound        = errors.New("sku not found")
+    ErrInvalidCategory    = errors.New("invalid category")
+    ErrProductArchived    = errors.New("product is archived")
+    ErrProductNotArchived = errors.New("product is not archived")
+    ErrLastActiveSKU      = errors.New("a published product must keep at least one active sku")
+    ErrProductInReview    = errors.New("product is already published or awaiting review")
+    ErrProductUnchanged   = errors.New("product must be edited before it is resubmitted")
+    ErrSellerNotApproved  = errors.New("seller must be approved before publishing products")
+    ErrProductScoreTooLow = errors.New("product quality score too low for publishing")
+    ErrPriceSell          = errors.New("selling price must be greater than zero")
+    ErrPriceMRP           = errors.New("MRP must not be below the selling price")
 )
 
 type ProductService struct {
-    DB *gorm.DB
+    DB                *gorm.DB
+    Store             storage.ObjectStore
+    ModerationService *ModerationService
 }
 
 func NewProductService() *ProductService {
     return &ProductService{
-        DB: db.GetDB(),
+        DB:                db.GetDB(),
+        Store:             storage.GetStore(),
+        ModerationService: NewModerationService(),
     }
 }
 
@@ -25,12 +49,41 @@ func NewProductService() *ProductService {
 func (ps *ProductService) CreateProduct(sellerID uint, req *CreateProductRequest) (*models.Product, error) {
     // Validate category exists
     var category models.Category
-    if err := ps.DB.First(&category, req.CategoryID).Error; err != nil {
-        return nil, errors.New("invalid category")
+    if err := ps.DB.Where("is_active = ?", true).First(&category, req.CategoryID).Error; err != nil {
+        return nil, ErrInvalidCategory
     }
     
-    // Generate content quality score
-    score := ps.calculateContentScore(req)
+    // Validate attributes against the category schema
+    schema, err := category.GetSchema()
+    if err != nil {
+        return nil, err
+    }
+    fieldErrs := validation.ValidateAttributes(schema, req.Attributes, false, "attributes.")
+    barcodes := make([]string, len(req.SKUs))
+    for i, skuReq := range req.SKUs {
+        fieldErrs.Merge(validation.ValidateAttributes(schema, skuReq.Attributes, true, fmt.Sprintf("skus[%d].attributes.", i)))
+        validatePrices(skuReq.PriceMRP, skuReq.PriceSell, fmt.Sprintf("skus[%d].", i), fieldErrs)
+        barcodes[i] = validateBarcode(skuReq.Barcode, fmt.Sprintf("skus[%d].barcode", i), fieldErrs)
+    }
+    if len(fieldErrs) > 0 {
+        return nil, fieldErrs
+    }
+    
+    // Build SKUs, codes are assigned once the product has an ID
+    skus := make([]models.SKU, len(req.SKUs))
+    for i, skuReq := range req.SKUs {
+        skus[i] = models.SKU{
+            PriceMRP:  skuReq.PriceMRP,
+            PriceSell: skuReq.PriceSell,
+            TaxPct:    skuReq.TaxPct,
+            Barcode:   barcodes[i],
+        }
+        
+        // Set attributes
+        if err := skus[i].SetAttributes(skuReq.Attributes); err != nil {
+            return nil, err
+        }
+    }
     
     // Create product
     product := &models.Product{
@@ -40,8 +93,17 @@ func (ps *ProductService) CreateProduct(sellerID uint, req *CreateProductRequest
         Description: req.Description,
         Brand:       req.Brand,
         Status:      models.ProductStatusDraft,
-        Score:       score,
     }
+    if err := product.SetAttributes(req.Attributes); err != nil {
+        return nil, err
+    }
+    
+    // Generate content quality score
+    breakdown, err := ps.scoreContent(ps.DB, product, skus, nil)
+    if err != nil {
+        return nil, err
+    }
+    product.Score = breakdown.Score
     
     // Begin transaction
     tx := ps.DB.Begin()
@@ -58,20 +120,19 @@ func (ps *ProductService) CreateProduct(sellerID uint, req *CreateProductRequest
     }
     
     // Create SKUs
-    for _, skuReq := range req.SKUs {
-        sku := &models.SKU{
-            ProductID: product.ID,
-            SKUCode:   ps.generateSKUCode(product.ID, skuReq.Attributes),
-            PriceMRP:  skuReq.PriceMRP,
-            PriceSell: skuReq.PriceSell,
-            TaxPct:    skuReq.TaxPct,
-            Barcode:   skuReq.Barcode,
-        }
+    for i, skuReq := range req.SKUs {
+        sku := &skus[i]
+        sku.ProductID = product.ID
+        sku.SellerID = sellerID
         
-        // Set attributes
-        if err := sku.SetAttributes(skuReq.Attributes); err != nil {
+        prefix := fmt.Sprintf("skus[%d].", i)
+        if err := ps.assignSKUCode(tx, sku, skuReq.SKUCode); err != nil {
             tx.Rollback()
-            return nil, err
+            return nil, skuFieldError(prefix, err)
+        }
+        if err := ps.checkBarcode(tx, sku); err != nil {
+            tx.Rollback()
+            return nil, skuFieldError(prefix, err)
         }
         
         if err := tx.Create(sku).Error; err != nil {
@@ -99,8 +160,16 @@ func (ps *ProductService) GetProduct(productID, sellerID uint) (*models.Product,
         Preload("Media").
         Where("id = ? AND seller_id = ?", productID, sellerID).
         First(&product).Error
-        
-    return &product, err
+    if err != nil {
+        return &product, err
+    }
+
+    for i := range product.Media {
+        if err := storage.ResolveMediaLinks(ps.Store, &product.Media[i], config.AppConfig.MediaURLExpiry); err != nil {
+            return nil, err
+        }
+    }
+    return &product, nil
 }
 
 // List seller products
@@ -108,23 +177,28 @@ func (ps *ProductService) ListProducts(sellerID uint, filters ProductFilters) ([
     var products []models.Product
     var total int64
     
-    query := ps.DB.Model(&models.Product{}).Where("seller_id = ?", sellerID)
+    query := ps.DB.Model(&models.Product{}).Where("products.seller_id = ?", sellerID)
     
     // Apply filters
     if filters.Status != nil {
-        query = query.Where("status = ?", *filters.Status)
+        query = query.Where("products.status = ?", *filters.Status)
     }
     if filters.CategoryID != nil {
-        query = query.Where("category_id = ?", *filters.CategoryID)
+        query = query.Where("products.category_id = ?", *filters.CategoryID)
     }
-    if filters.Search != "" {
-        query = query.Where("title ILIKE ? OR description ILIKE ?", 
-            "%"+filters.Search+"%", "%"+filters.Search+"%")
+    productSearch := search.NewProductSearch(filters.Search)
+    if productSearch != nil {
+        query = productSearch.Filter(query)
     }
     
     // Get total count
     query.Count(&total)
     
+    // Best matches first when searching, newest first otherwise
+    if productSearch != nil {
+        query = productSearch.OrderByRelevance(query)
+    }
+    
     // Apply pagination
     offset := (filters.Page - 1) * filters.Limit
     err := query.
@@ -132,85 +206,559 @@ func (ps *ProductService) ListProducts(sellerID uint, filters ProductFilters) ([
         Preload("SKUs").
         Offset(offset).
         Limit(filters.Limit).
-        Order("created_at DESC").
+        Order("products.created_at DESC").
         Find(&products).Error
         
     return products, total, err
 }
 
-// Publish product
-func (ps *ProductService) PublishProduct(productID, sellerID uint) error {
+// Submit a product for publication. It goes live once an admin approves
+// it; a rejected product can be resubmitted after it has been edited.
+func (ps *ProductService) PublishProduct(productID, sellerID, userID uint) (*models.Product, error) {
     // Validate product can be published
-    var product models.Product
-    if err := ps.DB.Where("id = ? AND seller_id = ?", productID, sellerID).First(&product).Error; err != nil {
+    product, err := ps.findProduct(ps.DB, productID, sellerID)
+    if err != nil {
+        return nil, err
+    }
+    switch product.Status {
+    case models.ProductStatusArchived:
+        return nil, ErrProductArchived
+    case models.ProductStatusPublished, models.ProductStatusPendingReview:
+        return nil, ErrProductInReview
+    }
+    
+    // Only approved sellers can list products
+    var seller models.Seller
+    if err := ps.DB.First(&seller, sellerID).Error; err != nil {
+        return nil, err
+    }
+    if seller.Status != models.SellerStatusApproved {
+        return nil, ErrSellerNotApproved
+    }
+    
+    // Check the minimum quality score of the product's category
+    breakdown, err := ps.GetScoreBreakdown(productID, sellerID)
+    if err != nil {
+        return nil, err
+    }
+    product.Score = breakdown.Score
+    if !breakdown.Publishable {
+        return nil, &ScoreTooLowError{Breakdown: breakdown}
+    }
+    
+    if product.Status == models.ProductStatusRejected && !product.EditedSinceRejection {
+        return nil, ErrProductUnchanged
+    }
+    
+    err = ps.DB.Transaction(func(tx *gorm.DB) error {
+        return ps.ModerationService.Submit(tx, product, models.ModerationActionSubmit, &userID)
+    })
+    if err != nil {
+        return nil, err
+    }
+    
+    return product, nil
+}
+
+// Update product fields. Changing a material field of a published product
+// takes it off the marketplace until it passes moderation again.
+func (ps *ProductService) UpdateProduct(productID, sellerID uint, req *UpdateProductRequest) (*models.Product, error) {
+    err := ps.DB.Transaction(func(tx *gorm.DB) error {
+        return ps.updateProduct(tx, productID, sellerID, req)
+    })
+    if err != nil {
+        return nil, err
+    }
+    
+    return ps.GetProduct(productID, sellerID)
+}
+
+// updateProduct applies req inside the caller's transaction
+func (ps *ProductService) updateProduct(tx *gorm.DB, productID, sellerID uint, req *UpdateProductRequest) error {
+    product, err := ps.findProduct(tx, productID, sellerID)
+    if err != nil {
         return err
     }
+    if product.Status == models.ProductStatusArchived {
+        return ErrProductArchived
+    }
+    
+    updates := map[string]interface{}{}
+    if req.Attributes != nil {
+        current, err := product.GetAttributes()
+        if err != nil {
+            return err
+        }
+        if sameAttributes(current, *req.Attributes) {
+            req.Attributes = nil
+        }
+    }
+    categoryChanged := req.CategoryID != nil && *req.CategoryID != product.CategoryID
+    if categoryChanged {
+        var count int64
+        tx.Model(&models.Category{}).Where("id = ? AND is_active = ?", *req.CategoryID, true).Count(&count)
+        if count == 0 {
+            return ErrInvalidCategory
+        }
+        updates["category_id"] = *req.CategoryID
+    }
+    if categoryChanged || req.Attributes != nil {
+        if err := ps.validateProductAttributes(tx, product, req, categoryChanged); err != nil {
+            return err
+        }
+    }
+    if req.Attributes != nil {
+        if err := product.SetAttributes(*req.Attributes); err != nil {
+            return err
+        }
+        updates["attributes"] = product.Attributes
+    }
+    if req.Title != nil && *req.Title != product.Title {
+        updates["title"] = *req.Title
+    }
+    if req.Description != nil && *req.Description != product.Description {
+        updates["description"] = *req.Description
+    }
+    if req.Brand != nil && *req.Brand != product.Brand {
+        updates["brand"] = *req.Brand
+    }
+    if len(updates) == 0 {
+        return nil
+    }
     
-    // Check minimum quality score
-    if product.Score < 60 {
-        return errors.New("product quality score too low for publishing")
+    // Every editable product field is material
+    if err := tx.Model(product).Updates(updates).Error; err != nil {
+        return err
+    }
+    return ps.afterContentChange(tx, product, true)
+}
+
+// Add a SKU to an existing product
+func (ps *ProductService) AddSKU(productID, sellerID uint, req *CreateSKURequest) (*models.SKU, error) {
+    var sku *models.SKU
+    err := ps.DB.Transaction(func(tx *gorm.DB) error {
+        var err error
+        sku, err = ps.addSKU(tx, productID, sellerID, req)
+        return err
+    })
+    if err != nil {
+        return nil, err
     }
     
-    // Update status
-    return ps.DB.Model(&product).Update("status", models.ProductStatusPublished).Error
+    return sku, nil
 }
 
-// Calculate content quality score
-func (ps *ProductService) calculateContentScore(req *CreateProductRequest) int {
-    score := 0
+// addSKU creates the SKU inside the caller's transaction
+func (ps *ProductService) addSKU(tx *gorm.DB, productID, sellerID uint, req *CreateSKURequest) (*models.SKU, error) {
+    sku := &models.SKU{
+        ProductID: productID,
+        SellerID:  sellerID,
+        PriceMRP:  req.PriceMRP,
+        PriceSell: req.PriceSell,
+        TaxPct:    req.TaxPct,
+        IsActive:  true,
+    }
+    if err := sku.SetAttributes(req.Attributes); err != nil {
+        return nil, err
+    }
+    
+    product, err := ps.findProduct(tx, productID, sellerID)
+    if err != nil {
+        return nil, err
+    }
+    if product.Status == models.ProductStatusArchived {
+        return nil, ErrProductArchived
+    }
     
-    // Title quality (0-20 points)
-    if len(req.Title) >= 10 && len(req.Title) <= 100 {
-        score += 20
-    } else if len(req.Title) >= 5 {
-        score += 10
+    schema, err := ps.categorySchema(tx, product.CategoryID)
+    if err != nil {
+        return nil, err
+    }
+    fieldErrs := validation.ValidateAttributes(schema, req.Attributes, true, "attributes.")
+    validatePrices(sku.PriceMRP, sku.PriceSell, "", fieldErrs)
+    sku.Barcode = validateBarcode(req.Barcode, "barcode", fieldErrs)
+    if len(fieldErrs) > 0 {
+        return nil, fieldErrs
     }
     
-    // Description quality (0-25 points)
-    if len(req.Description) >= 100 && len(req.Description) <= 1000 {
-        score += 25
-    } else if len(req.Description) >= 50 {
-        score += 15
+    if err := ps.assignSKUCode(tx, sku, req.SKUCode); err != nil {
+        return nil, skuFieldError("", err)
+    }
+    if err := ps.checkBarcode(tx, sku); err != nil {
+        return nil, skuFieldError("", err)
+    }
+    
+    if err := tx.Create(sku).Error; err != nil {
+        return nil, err
+    }
+    if err := ps.afterContentChange(tx, product, true); err != nil {
+        return nil, err
+    }
+    return sku, nil
+}
+
+// Update a SKU. Price and tax changes apply immediately; attribute and
+// barcode changes of a published product send it back through review.
+func (ps *ProductService) UpdateSKU(productID, sellerID, skuID uint, req *UpdateSKURequest) (*models.SKU, error) {
+    var sku *models.SKU
+    err := ps.DB.Transaction(func(tx *gorm.DB) error {
+        var err error
+        sku, err = ps.updateSKU(tx, productID, sellerID, skuID, req)
+        return err
+    })
+    if err != nil {
+        return nil, err
     }
     
-    // Brand presence (0-10 points)
-    if req.Brand != "" {
-        score += 10
+    return sku, nil
+}
+
+// updateSKU applies req inside the caller's transaction
+func (ps *ProductService) updateSKU(tx *gorm.DB, productID, sellerID, skuID uint, req *UpdateSKURequest) (*models.SKU, error) {
+    var sku models.SKU
+    product, err := ps.findProduct(tx, productID, sellerID)
+    if err != nil {
+        return nil, err
+    }
+    if product.Status == models.ProductStatusArchived {
+        return nil, ErrProductArchived
+    }
+    if err := tx.Where("id = ? AND product_id = ?", skuID, productID).First(&sku).Error; err != nil {
+        return nil, ErrSKUNotFound
     }
     
-    // SKU completeness (0-25 points)
-    if len(req.SKUs) > 0 {
-        score += 15
-        for _, sku := range req.SKUs {
-            if sku.PriceMRP.GreaterThan(sku.PriceSell) && sku.PriceSell.GreaterThan(decimal.Zero) {
-                score += 10
-                break
+    material := false
+    if req.Attributes != nil {
+        current, err := sku.GetAttributes()
+        if err != nil {
+            return nil, err
+        }
+        if sameAttributes(current, *req.Attributes) {
+            req.Attributes = nil
+        }
+    }
+    if req.Attributes != nil {
+        schema, err := ps.categorySchema(tx, product.CategoryID)
+        if err != nil {
+            return nil, err
+        }
+        if fieldErrs := validation.ValidateAttributes(schema, *req.Attributes, true, "attributes."); len(fieldErrs) > 0 {
+            return nil, fieldErrs
+        }
+        if err := sku.SetAttributes(*req.Attributes); err != nil {
+            return nil, err
+        }
+        material = true
+    }
+    if req.Barcode != nil {
+        fieldErrs := validation.FieldErrors{}
+        barcode := validateBarcode(*req.Barcode, "barcode", fieldErrs)
+        if len(fieldErrs) > 0 {
+            return nil, fieldErrs
+        }
+        if barcode != sku.Barcode {
+            sku.Barcode = barcode
+            material = true
+        }
+    }
+    if req.PriceMRP != nil {
+        sku.PriceMRP = *req.PriceMRP
+    }
+    if req.PriceSell != nil {
+        sku.PriceSell = *req.PriceSell
+    }
+    if req.TaxPct != nil {
+        sku.TaxPct = *req.TaxPct
+    }
+    if req.PriceMRP != nil || req.PriceSell != nil {
+        fieldErrs := validation.FieldErrors{}
+        validatePrices(sku.PriceMRP, sku.PriceSell, "", fieldErrs)
+        if len(fieldErrs) > 0 {
+            return nil, fieldErrs
+        }
+    }
+    if req.IsActive != nil && *req.IsActive != sku.IsActive {
+        if !*req.IsActive {
+            if err := ps.ensureOtherActiveSKU(tx, product, sku.ID); err != nil {
+                return nil, err
             }
         }
+        sku.IsActive = *req.IsActive
+    }
+    
+    // Recheck code and barcode, attribute edits may clash with a sibling
+    var requestedCode string
+    if req.SKUCode != nil && *req.SKUCode != sku.SKUCode {
+        requestedCode = *req.SKUCode
+    }
+    if requestedCode != "" || req.Attributes != nil {
+        if err := ps.assignSKUCode(tx, &sku, requestedCode); err != nil {
+            return nil, skuFieldError("", err)
+        }
+    }
+    if err := ps.checkBarcode(tx, &sku); err != nil {
+        return nil, skuFieldError("", err)
+    }
+    
+    if err := tx.Save(&sku).Error; err != nil {
+        return nil, err
+    }
+    if err := ps.afterContentChange(tx, product, material); err != nil {
+        return nil, err
+    }
+    return &sku, nil
+}
+
+// Deactivate a SKU, it stays on record but is no longer sold
+func (ps *ProductService) DeactivateSKU(productID, sellerID, skuID uint) error {
+    inactive := false
+    _, err := ps.UpdateSKU(productID, sellerID, skuID, &UpdateSKURequest{IsActive: &inactive})
+    return err
+}
+
+// Archive a product, hiding it from the marketplace and blocking edits
+func (ps *ProductService) ArchiveProduct(productID, sellerID, userID uint) error {
+    product, err := ps.findProduct(ps.DB, productID, sellerID)
+    if err != nil {
+        return err
+    }
+    if product.Status == models.ProductStatusArchived {
+        return ErrProductArchived
     }
     
-    // Media presence (0-20 points) - will be updated when media is added
+    return ps.DB.Transaction(func(tx *gorm.DB) error {
+        return ps.ModerationService.Transition(tx, product, models.ProductStatusArchived,
+            models.ModerationActionArchive, &userID, nil, "")
+    })
+}
+
+// Unarchive a product back to draft, it has to be published again
+func (ps *ProductService) UnarchiveProduct(productID, sellerID, userID uint) error {
+    product, err := ps.findProduct(ps.DB, productID, sellerID)
+    if err != nil {
+        return err
+    }
+    if product.Status != models.ProductStatusArchived {
+        return ErrProductNotArchived
+    }
     
-    return score
+    return ps.DB.Transaction(func(tx *gorm.DB) error {
+        if err := ps.ModerationService.Transition(tx, product, models.ProductStatusDraft,
+            models.ModerationActionUnarchive, &userID, nil, ""); err != nil {
+            return err
+        }
+        _, err := ps.RecalculateScore(tx, product.ID)
+        return err
+    })
+}
+
+// Load the attribute schema of a category
+func (ps *ProductService) categorySchema(tx *gorm.DB, categoryID uint) (*models.CategorySchema, error) {
+    var category models.Category
+    if err := tx.First(&category, categoryID).Error; err != nil {
+        return nil, ErrInvalidCategory
+    }
+    return category.GetSchema()
 }
 
-// Generate SKU code
-func (ps *ProductService) generateSKUCode(productID uint, attrs models.SKUAttributes) string {
-    base := fmt.Sprintf("PRD%d", productID)
-    var parts []string
+// Validate the product's attributes after an update. Moving the product to
+// another category also revalidates every SKU against the new schema.
+func (ps *ProductService) validateProductAttributes(tx *gorm.DB, product *models.Product, req *UpdateProductRequest, categoryChanged bool) error {
+    categoryID := product.CategoryID
+    if categoryChanged {
+        categoryID = *req.CategoryID
+    }
+    schema, err := ps.categorySchema(tx, categoryID)
+    if err != nil {
+        return err
+    }
     
-    if attrs.Color != "" {
-        parts = append(parts, strings.ToUpper(attrs.Color[:1]))
+    attrs, err := product.GetAttributes()
+    if err != nil {
+        return err
     }
-    if attrs.Size != "" {
-        parts = append(parts, strings.ToUpper(attrs.Size))
+    if req.Attributes != nil {
+        attrs = *req.Attributes
     }
+    fieldErrs := validation.ValidateAttributes(schema, attrs, false, "attributes.")
     
-    if len(parts) > 0 {
-        return fmt.Sprintf("%s-%s", base, strings.Join(parts, ""))
+    if categoryChanged {
+        var skus []models.SKU
+        if err := tx.Where("product_id = ?", product.ID).Find(&skus).Error; err != nil {
+            return err
+        }
+        for _, sku := range skus {
+            skuAttrs, err := sku.GetAttributes()
+            if err != nil {
+                return err
+            }
+            fieldErrs.Merge(validation.ValidateAttributes(schema, skuAttrs, true, fmt.Sprintf("skus[%s].attributes.", sku.SKUCode)))
+        }
     }
     
-    return base
+    if len(fieldErrs) > 0 {
+        return fieldErrs
+    }
+    return nil
+}
+
+func (ps *ProductService) findProduct(tx *gorm.DB, productID, sellerID uint) (*models.Product, error) {
+    var product models.Product
+    if err := tx.Where("id = ? AND seller_id = ?", productID, sellerID).First(&product).Error; err != nil {
+        return nil, ErrProductNotFound
+    }
+    return &product, nil
+}
+
+// Rerun the content score after an edit, which also lets a rejected product
+// be resubmitted, and send a published product back through moderation
+// when a material field changed, otherwise refresh it in the marketplace
+// search
+func (ps *ProductService) afterContentChange(tx *gorm.DB, product *models.Product, material bool) error {
+    breakdown, err := ps.recalculateScore(tx, product.ID, true)
+    if err != nil {
+        return err
+    }
+    if err := ps.enforceMinScore(tx, product, breakdown); err != nil {
+        return err
+    }
+    if product.Status != models.ProductStatusPublished {
+        return nil
+    }
+    if material {
+        return ps.ModerationService.Submit(tx, product, models.ModerationActionEdit, nil)
+    }
+    return search.RecordEvent(tx, product.ID, models.SearchEventUpdate)
+}
+
+// A published product must stay purchasable in at least one variant
+func (ps *ProductService) ensureOtherActiveSKU(tx *gorm.DB, product *models.Product, skuID uint) error {
+    if product.Status != models.ProductStatusPublished {
+        return nil
+    }
+    var count int64
+    tx.Model(&models.SKU{}).Where("product_id = ? AND id <> ? AND is_active = ?", product.ID, skuID, true).Count(&count)
+    if count == 0 {
+        return ErrLastActiveSKU
+    }
+    return nil
+}
+
+// Recalculate and store the content score of a product, e.g. after its
+// media changed
+func (ps *ProductService) RecalculateScore(tx *gorm.DB, productID uint) (int, error) {
+    breakdown, err := ps.recalculateScore(tx, productID, true)
+    if err != nil {
+        return 0, err
+    }
+    
+    var product models.Product
+    if err := tx.First(&product, productID).Error; err != nil {
+        return 0, err
+    }
+    if err := ps.enforceMinScore(tx, &product, breakdown); err != nil {
+        return 0, err
+    }
+    return breakdown.Score, nil
+}
+
+// Take a live or queued product below its category's minimum score off
+// the marketplace, rejecting it with the score as reason, the same bar
+// PublishProduct applies
+func (ps *ProductService) enforceMinScore(tx *gorm.DB, product *models.Product, breakdown *ScoreBreakdown) error {
+    if breakdown.Publishable {
+        return nil
+    }
+    if product.Status != models.ProductStatusPublished && product.Status != models.ProductStatusPendingReview {
+        return nil
+    }
+    
+    reasons := []models.RejectionReason{{
+        Code:    models.RejectionLowScore,
+        Field:   "score",
+        Message: fmt.Sprintf("Quality score %d is below the minimum of %d for this category", breakdown.Score, breakdown.MinPublishScore),
+    }}
+    return ps.ModerationService.Transition(tx, product, models.ProductStatusRejected,
+        models.ModerationActionAutoReject, nil, reasons, "")
+}
+
+// Recalculate the content score. edited records an actual edit of the
+// product, which unlocks resubmission after a rejection; a refresh of the
+// score alone must not.
+func (ps *ProductService) recalculateScore(tx *gorm.DB, productID uint, edited bool) (*ScoreBreakdown, error) {
+    var product models.Product
+    err := tx.
+        Preload("SKUs", "is_active = ?", true).
+        Preload("Media").
+        First(&product, productID).Error
+    if err != nil {
+        return nil, err
+    }
+    
+    breakdown, err := ps.scoreContent(tx, &product, product.SKUs, product.Media)
+    if err != nil {
+        return nil, err
+    }
+    if edited {
+        updates := map[string]interface{}{"score": breakdown.Score}
+        if product.Status == models.ProductStatusRejected {
+            updates["edited_since_rejection"] = true
+        }
+        err = tx.Model(&product).Updates(updates).Error
+    } else if breakdown.Score != product.Score {
+        err = tx.Model(&product).UpdateColumn("score", breakdown.Score).Error
+    }
+    if err != nil {
+        return nil, err
+    }
+    
+    return breakdown, nil
+}
+
+// Check the selling price and MRP of a SKU, recording field errors under
+// prefix
+func validatePrices(mrp, sell decimal.Decimal, prefix string, fieldErrs validation.FieldErrors) {
+    if !sell.IsPositive() {
+        fieldErrs[prefix+"price_sell"] = ErrPriceSell.Error()
+    } else if mrp.LessThan(sell) {
+        fieldErrs[prefix+"price_mrp"] = ErrPriceMRP.Error()
+    }
+}
+
+// Attributes are equal when they encode to the same JSON, so numbers
+// decoded from a request and from the database compare alike
+func sameAttributes(a, b models.Attributes) bool {
+    dataA, errA := json.Marshal(a)
+    dataB, errB := json.Marshal(b)
+    return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// Normalise and checksum-validate an optional barcode, recording a field
+// error under field when it is invalid
+func validateBarcode(barcode, field string, fieldErrs validation.FieldErrors) string {
+    barcode = validation.NormalizeBarcode(barcode)
+    if barcode == "" {
+        return ""
+    }
+    if err := validation.ValidateBarcode(barcode); err != nil {
+        fieldErrs[field] = err.Error()
+    }
+    return barcode
+}
+
+// Report SKU code, barcode and duplicate attribute conflicts as field
+// errors so the client can tell which SKU of a request is at fault
+func skuFieldError(prefix string, err error) error {
+    switch {
+    case errors.Is(err, ErrSKUCodeTaken), errors.Is(err, ErrInvalidSKUCode):
+        return validation.FieldErrors{prefix + "sku_code": err.Error()}
+    case errors.Is(err, ErrBarcodeTaken):
+        return validation.FieldErrors{prefix + "barcode": err.Error()}
+    case errors.Is(err, ErrDuplicateSKU):
+        return validation.FieldErrors{prefix + "attributes": err.Error()}
+    default:
+        return err
+    }
 }
 
 // Request DTOs
@@ -219,17 +767,37 @@ type CreateProductRequest struct {
     Title       string                  `json:"title" binding:"required,min=5,max=100"`
     Description string                  `json:"description" binding:"required,min=10"`
     Brand       string                  `json:"brand"`
+    Attributes  models.Attributes       `json:"attributes"`
     SKUs        []CreateSKURequest      `json:"skus" binding:"required,min=1"`
 }
 
 type CreateSKURequest struct {
-    Attributes models.SKUAttributes    `json:"attributes"`
+    SKUCode    string                  `json:"sku_code" binding:"max=64"` // Generated when empty
+    Attributes models.Attributes       `json:"attributes"`
     PriceMRP   decimal.Decimal         `json:"price_mrp" binding:"required"`
     PriceSell  decimal.Decimal         `json:"price_sell" binding:"required"`
     TaxPct     decimal.Decimal         `json:"tax_pct"`
     Barcode    string                  `json:"barcode"`
 }
 
+type UpdateProductRequest struct {
+    CategoryID  *uint                   `json:"category_id"`
+    Title       *string                 `json:"title" binding:"omitempty,min=5,max=100"`
+    Description *string                 `json:"description" binding:"omitempty,min=10"`
+    Brand       *string                 `json:"brand"`
+    Attributes  *models.Attributes      `json:"attributes"`
+}
+
+type UpdateSKURequest struct {
+    SKUCode    *string                 `json:"sku_code" binding:"omitempty,min=1,max=64"`
+    Attributes *models.Attributes      `json:"attributes"`
+    PriceMRP   *decimal.Decimal        `json:"price_mrp"`
+    PriceSell  *decimal.Decimal        `json:"price_sell"`
+    TaxPct     *decimal.Decimal        `json:"tax_pct"`
+    Barcode    *string                 `json:"barcode"`
+    IsActive   *bool                   `json:"is_active"`
+}
+
 type ProductFilters struct {
     Status     *int   `form:"status"`
     CategoryID *uint  `form:"category_id"`
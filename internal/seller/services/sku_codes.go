@@ -0,0 +1,188 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/validation"
+	"gocom/main/internal/models"
+)
+
+var (
+	ErrSKUCodeTaken    = errors.New("sku code is already used by another sku of this seller")
+	ErrInvalidSKUCode  = errors.New("sku code must be 1-64 letters, digits, '.', '_', '/' or '-'")
+	ErrDuplicateSKU    = errors.New("product already has a sku with the same attributes")
+	ErrBarcodeTaken    = errors.New("barcode is already used by another sku of this seller")
+	ErrSKULookupFilter = errors.New("either code or barcode is required")
+)
+
+var (
+	skuCodePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,63}$`)
+	skuCodeNonAlnum = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// Readable attribute segments kept in a generated code; the hash suffix
+// covers every attribute regardless
+const (
+	skuCodeMaxSegments   = 3
+	skuCodeSegmentLength = 4
+)
+
+// Suffix lengths tried in turn when a generated code is already taken
+var skuCodeHashLengths = []int{4, 8, 16}
+
+// Find a SKU of the seller by its code or barcode. Barcodes are checksum
+// validated, and a UPC-A code also matches its EAN-13 form.
+func (ps *ProductService) LookupSKU(sellerID uint, code, barcode string) (*models.SKU, error) {
+	query := ps.DB.Preload("Product").Where("seller_id = ?", sellerID)
+
+	switch {
+	case code != "":
+		query = query.Where("sku_code = ?", strings.TrimSpace(code))
+	case barcode != "":
+		barcode = validation.NormalizeBarcode(barcode)
+		if err := validation.ValidateBarcode(barcode); err != nil {
+			return nil, err
+		}
+		query = query.Where("barcode IN ?", validation.BarcodeVariants(barcode))
+	default:
+		return nil, ErrSKULookupFilter
+	}
+
+	var sku models.SKU
+	if err := query.First(&sku).Error; err != nil {
+		return nil, ErrSKUNotFound
+	}
+	return &sku, nil
+}
+
+// Give a SKU its code: the seller's own code when requested, otherwise a
+// deterministic one derived from the product and all SKU attributes.
+// sku.SellerID and sku.ProductID must be set.
+func (ps *ProductService) assignSKUCode(tx *gorm.DB, sku *models.SKU, requested string) error {
+	attrs, err := sku.GetAttributes()
+	if err != nil {
+		return err
+	}
+
+	// Identical attributes would make two SKUs indistinguishable
+	var siblings []models.SKU
+	if err := tx.Where("product_id = ? AND id <> ?", sku.ProductID, sku.ID).Find(&siblings).Error; err != nil {
+		return err
+	}
+	canonical := canonicalAttributes(attrs)
+	for _, sibling := range siblings {
+		siblingAttrs, err := sibling.GetAttributes()
+		if err != nil {
+			return err
+		}
+		if canonicalAttributes(siblingAttrs) == canonical {
+			return ErrDuplicateSKU
+		}
+	}
+
+	if requested != "" {
+		code := strings.TrimSpace(requested)
+		if !skuCodePattern.MatchString(code) {
+			return ErrInvalidSKUCode
+		}
+		if ps.skuCodeTaken(tx, sku, code) {
+			return ErrSKUCodeTaken
+		}
+		sku.SKUCode = code
+		return nil
+	}
+
+	// Keep the code of an existing SKU, references to it live outside
+	if sku.SKUCode != "" {
+		return nil
+	}
+
+	for _, hashLength := range skuCodeHashLengths {
+		code := generateSKUCode(sku.ProductID, attrs, hashLength)
+		if !ps.skuCodeTaken(tx, sku, code) {
+			sku.SKUCode = code
+			return nil
+		}
+	}
+	return ErrSKUCodeTaken
+}
+
+// Reject a barcode another SKU of the seller already carries
+func (ps *ProductService) checkBarcode(tx *gorm.DB, sku *models.SKU) error {
+	if sku.Barcode == "" {
+		return nil
+	}
+
+	var count int64
+	tx.Model(&models.SKU{}).
+		Where("seller_id = ? AND barcode IN ? AND id <> ?", sku.SellerID, validation.BarcodeVariants(sku.Barcode), sku.ID).
+		Count(&count)
+	if count > 0 {
+		return ErrBarcodeTaken
+	}
+	return nil
+}
+
+func (ps *ProductService) skuCodeTaken(tx *gorm.DB, sku *models.SKU, code string) bool {
+	var count int64
+	tx.Model(&models.SKU{}).
+		Where("seller_id = ? AND sku_code = ? AND id <> ?", sku.SellerID, code, sku.ID).
+		Count(&count)
+	return count > 0
+}
+
+// generateSKUCode builds codes like PRD12-RED-L-K7QX: product ID, short
+// readable segments of the first attribute values in key order and a hash
+// of every attribute, so "Red/L" and "Rose/L" never share a code
+func generateSKUCode(productID uint, attrs models.Attributes, hashLength int) string {
+	parts := []string{fmt.Sprintf("PRD%d", productID)}
+
+	for _, key := range sortedKeys(attrs) {
+		if len(parts) > skuCodeMaxSegments {
+			break
+		}
+		segment := skuCodeNonAlnum.ReplaceAllString(strings.ToUpper(attrs.String(key)), "")
+		if len(segment) > skuCodeSegmentLength {
+			segment = segment[:skuCodeSegmentLength]
+		}
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+
+	sum := sha1.Sum([]byte(canonicalAttributes(attrs)))
+	hash := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:])
+	parts = append(parts, hash[:hashLength])
+
+	return strings.Join(parts, "-")
+}
+
+// canonicalAttributes renders attributes independently of key order and
+// letter case
+func canonicalAttributes(attrs models.Attributes) string {
+	var pairs []string
+	for _, key := range sortedKeys(attrs) {
+		value := strings.ToLower(strings.TrimSpace(attrs.String(key)))
+		if value != "" {
+			pairs = append(pairs, strings.ToLower(key)+"="+value)
+		}
+	}
+	return strings.Join(pairs, ";")
+}
+
+func sortedKeys(attrs models.Attributes) []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gocom/main/internal/common/config"
+	"gocom/main/internal/common/db"
+	"gocom/main/internal/integrations/storage"
+	"gocom/main/internal/models"
+)
+
+const tempUploadsBucket = "temp-uploads"
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadExpired        = errors.New("upload has expired")
+	ErrUploadNotReceived    = errors.New("object has not been uploaded yet")
+	ErrUploadMismatch       = errors.New("uploaded object does not match the declared size or content type")
+	ErrInvalidUploadPurpose = errors.New("unsupported upload purpose")
+	ErrUploadProduct        = errors.New("product_id must reference a product of this seller")
+)
+
+type UploadService struct {
+	DB           *gorm.DB
+	Store        storage.ObjectStore
+	MediaService *MediaService
+	KYCService   *KYCService
+}
+
+func NewUploadService() *UploadService {
+	return &UploadService{
+		DB:           db.GetDB(),
+		Store:        storage.GetStore(),
+		MediaService: NewMediaService(),
+		KYCService:   NewKYCService(),
+	}
+}
+
+// Create an upload session and a presigned PUT URL into temp-uploads. The
+// content type and length are signed into the URL so the client cannot
+// upload anything other than what it declared.
+func (us *UploadService) CreateUpload(sellerID, userID uint, req *CreateUploadRequest) (*UploadResponse, error) {
+	switch req.Purpose {
+	case models.UploadPurposeProductMedia:
+		if req.ProductID == nil {
+			return nil, ErrUploadProduct
+		}
+		var count int64
+		us.DB.Model(&models.Product{}).Where("id = ? AND seller_id = ?", *req.ProductID, sellerID).Count(&count)
+		if count == 0 {
+			return nil, ErrUploadProduct
+		}
+		if _, ok := mediaContentTypes[req.ContentType]; !ok {
+			return nil, ErrMediaContentType
+		}
+		if req.Size > config.AppConfig.MediaMaxVideoBytes {
+			return nil, ErrMediaTooLarge
+		}
+	case models.UploadPurposeKYCDocument:
+		if !models.IsValidKYCType(req.KYCType) {
+			return nil, ErrInvalidKYCType
+		}
+		if _, ok := kycContentTypes[req.ContentType]; !ok {
+			return nil, ErrKYCContentType
+		}
+		if req.Size > config.AppConfig.KYCMaxUploadBytes {
+			return nil, ErrKYCTooLarge
+		}
+	default:
+		return nil, ErrInvalidUploadPurpose
+	}
+
+	expiry := config.AppConfig.PresignExpiry
+	session := &models.UploadSession{
+		SellerID:    sellerID,
+		UserID:      userID,
+		Purpose:     req.Purpose,
+		ProductID:   req.ProductID,
+		KYCType:     req.KYCType,
+		ObjectName:  fmt.Sprintf("sellers/%d/%d", sellerID, time.Now().UnixNano()),
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Status:      models.UploadStatusPending,
+		ExpiresAt:   time.Now().Add(expiry),
+	}
+	if err := us.DB.Create(session).Error; err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", req.ContentType)
+	headers.Set("Content-Length", strconv.FormatInt(req.Size, 10))
+
+	url, err := us.Store.PresignPut(tempUploadsBucket, session.ObjectName, expiry, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadResponse{
+		UploadID:  session.ID,
+		Method:    http.MethodPut,
+		URL:       url,
+		Headers:   map[string]string{"Content-Type": req.ContentType, "Content-Length": headers.Get("Content-Length")},
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// Get a pending upload session of the seller
+func (us *UploadService) GetUpload(sellerID, uploadID uint) (*models.UploadSession, error) {
+	var session models.UploadSession
+	err := us.DB.
+		Where("id = ? AND seller_id = ? AND status = ?", uploadID, sellerID, models.UploadStatusPending).
+		First(&session).Error
+	if err != nil {
+		return nil, ErrUploadNotFound
+	}
+	return &session, nil
+}
+
+// Finalise an upload: verify the temp object, move it into its permanent
+// bucket through the regular media or KYC pipeline and create the row
+func (us *UploadService) FinaliseUpload(session *models.UploadSession, req *FinaliseUploadRequest) (interface{}, error) {
+	info, err := us.Store.Stat(tempUploadsBucket, session.ObjectName)
+	if err != nil {
+		if session.ExpiresAt.Before(time.Now()) {
+			return nil, ErrUploadExpired
+		}
+		return nil, ErrUploadNotReceived
+	}
+	if info.Size != session.Size || info.ContentType != session.ContentType {
+		return nil, ErrUploadMismatch
+	}
+
+	// Claiming the session and creating the row in one transaction means a
+	// concurrent or retried finalise, or the sweeper, finds it taken
+	var result interface{}
+	err = us.DB.Transaction(func(tx *gorm.DB) error {
+		claim := tx.Model(&models.UploadSession{}).
+			Where("id = ? AND status = ?", session.ID, models.UploadStatusPending).
+			Update("status", models.UploadStatusFinalised)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return ErrUploadNotFound
+		}
+
+		object, err := us.Store.Get(tempUploadsBucket, session.ObjectName)
+		if err != nil {
+			return err
+		}
+		defer object.Close()
+
+		// Content is re-sniffed and validated by the destination pipeline
+		switch session.Purpose {
+		case models.UploadPurposeProductMedia:
+			result, err = us.MediaService.uploadMedia(tx, *session.ProductID, object, info.Size, req.AltText)
+		case models.UploadPurposeKYCDocument:
+			result, err = us.KYCService.uploadDocument(tx, session.SellerID, session.KYCType, object, info.Size)
+		default:
+			err = ErrInvalidUploadPurpose
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.Status = models.UploadStatusFinalised
+	us.Store.Delete(tempUploadsBucket, session.ObjectName)
+
+	return result, nil
+}
+
+// SweepTempUploads deletes temp objects of abandoned sessions and any
+// stray temp object older than the configured maximum age
+func (us *UploadService) SweepTempUploads() error {
+	cutoff := time.Now().Add(-config.AppConfig.TempUploadMaxAge)
+
+	var sessions []models.UploadSession
+	if err := us.DB.
+		Where("status = ? AND expires_at < ?", models.UploadStatusPending, cutoff).
+		Find(&sessions).Error; err != nil {
+		return err
+	}
+	expired := 0
+	for _, session := range sessions {
+		// Expire the session before touching its object; a finalise that
+		// claimed it first keeps the object
+		claim := us.DB.Model(&models.UploadSession{}).
+			Where("id = ? AND status = ?", session.ID, models.UploadStatusPending).
+			Update("status", models.UploadStatusExpired)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+		us.Store.Delete(tempUploadsBucket, session.ObjectName)
+		expired++
+	}
+
+	// Stray objects of sessions that can still be finalised are kept
+	var pending []string
+	if err := us.DB.Model(&models.UploadSession{}).
+		Where("status = ?", models.UploadStatusPending).
+		Pluck("object_name", &pending).Error; err != nil {
+		return err
+	}
+	inUse := make(map[string]bool, len(pending))
+	for _, name := range pending {
+		inUse[name] = true
+	}
+
+	objects, err := us.Store.List(tempUploadsBucket, "")
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if object.LastModified.Before(cutoff) && !inUse[object.Key] {
+			us.Store.Delete(tempUploadsBucket, object.Key)
+		}
+	}
+
+	log.Printf("Swept temp uploads: %d expired sessions", expired)
+	return nil
+}
+
+// StartUploadSweeper runs SweepTempUploads in the background every interval
+func (us *UploadService) StartUploadSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := us.SweepTempUploads(); err != nil {
+				log.Printf("Temp upload sweep failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Request DTOs
+type CreateUploadRequest struct {
+	Purpose     string `json:"purpose" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+	ProductID   *uint  `json:"product_id"`
+	KYCType     string `json:"kyc_type"`
+}
+
+type FinaliseUploadRequest struct {
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+// Response DTOs
+type UploadResponse struct {
+	UploadID  uint              `json:"upload_id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
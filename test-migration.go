@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 
+	adminservices "gocom/main/internal/admin/services"
 	"gocom/main/internal/common/config"
 	"gocom/main/internal/common/db"
 	"gocom/main/internal/models"
@@ -11,13 +12,25 @@ import (
 func main() {
 	config.LoadConfig()
 	db.ConnectMySQL()
+	if err := adminservices.BackfillCategorySlugs(db.GetDB()); err != nil {
+		log.Fatalf("Category slug backfill failed: %v", err)
+	}
 	err := db.AutoMigrate(
 		&models.User{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
 		&models.Seller{},
 		&models.SellerUser{},
 		&models.KYC{},
 		&models.Category{},
+		&models.CategorySchemaVersion{},
 		&models.Product{},
+		&models.ProductModeration{},
+		&models.ProhibitedKeyword{},
+		&models.RestrictedBrand{},
+		&models.BrandAuthorization{},
+		&models.SearchEvent{},
+		&models.SearchQuery{},
 		&models.SKU{},
 		&models.Inventory{},
 		&models.Cart{},
@@ -33,6 +46,9 @@ func main() {
 		&models.Address{},
 		&models.AuditLog{},
 		&models.Media{},
+		&models.UploadSession{},
+		&models.ImportJob{},
+		&models.ExportJob{},
 	)
 
 	if err != nil {